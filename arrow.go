@@ -0,0 +1,235 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The Apache Arrow decimal128(p, s) and decimal256(p, s) types store each
+// value as its unscaled integer (see AppendUnscaledBytes) in a little-endian
+// two's-complement integer of 16 or 32 bytes. The value must have at most p
+// digits.
+const (
+	// MaxArrowDecimal128Precision is the largest precision of an Arrow
+	// decimal128.
+	MaxArrowDecimal128Precision = 38
+	// MaxArrowDecimal256Precision is the largest precision of an Arrow
+	// decimal256.
+	MaxArrowDecimal256Precision = 76
+
+	arrowDecimal128Width = 16
+	arrowDecimal256Width = 32
+)
+
+// AppendArrowDecimal128 appends the values of src to buf as Arrow
+// decimal128(precision, scale) values and returns the extended buffer. A value
+// is appended for every element of src. Elements that are not finite, cannot
+// be represented exactly with the scale, or have more than precision digits
+// are written as zero, and reported in the returned ElementErrors.
+func AppendArrowDecimal128(buf []byte, src []Decimal, precision, scale int32) ([]byte, error) {
+	return appendArrow(buf, src, precision, scale, arrowDecimal128Width, MaxArrowDecimal128Precision)
+}
+
+// AppendArrowDecimal256 is like AppendArrowDecimal128, but appends Arrow
+// decimal256(precision, scale) values.
+func AppendArrowDecimal256(buf []byte, src []Decimal, precision, scale int32) ([]byte, error) {
+	return appendArrow(buf, src, precision, scale, arrowDecimal256Width, MaxArrowDecimal256Precision)
+}
+
+// DecodeArrowDecimal128 sets the elements of dst to the Arrow
+// decimal128(precision, scale) values in src, which must hold exactly
+// len(dst) values. The exponent of each element is -scale. Values with more
+// than precision digits are reported in the returned ElementErrors, and their
+// elements are left unchanged.
+func DecodeArrowDecimal128(dst []Decimal, src []byte, precision, scale int32) error {
+	return decodeArrow(dst, src, precision, scale, arrowDecimal128Width, MaxArrowDecimal128Precision)
+}
+
+// DecodeArrowDecimal256 is like DecodeArrowDecimal128, but decodes Arrow
+// decimal256(precision, scale) values.
+func DecodeArrowDecimal256(dst []Decimal, src []byte, precision, scale int32) error {
+	return decodeArrow(dst, src, precision, scale, arrowDecimal256Width, MaxArrowDecimal256Precision)
+}
+
+func checkArrowType(precision, scale, maxPrecision int32) error {
+	if precision < 1 || precision > maxPrecision {
+		return fmt.Errorf("arrow decimal: precision %d out of range [1, %d]", precision, maxPrecision)
+	}
+	if scale < -MaxExponent || scale > -MinExponent {
+		return fmt.Errorf("arrow decimal: scale %d out of range", scale)
+	}
+	return nil
+}
+
+func appendArrow(
+	buf []byte, src []Decimal, precision, scale int32, width int, maxPrecision int32,
+) ([]byte, error) {
+	if err := checkArrowType(precision, scale, maxPrecision); err != nil {
+		return nil, err
+	}
+	var errs ElementErrors
+	for i := range src {
+		var err error
+		start := len(buf)
+		buf, err = appendArrowValue(buf, &src[i], precision, scale, width)
+		if err != nil {
+			errs = append(errs, ElementError{Index: i, Err: err})
+			buf = buf[:start]
+			for j := 0; j < width; j++ {
+				buf = append(buf, 0)
+			}
+		}
+	}
+	if errs != nil {
+		return buf, errs
+	}
+	return buf, nil
+}
+
+// appendArrowValue appends d as a little-endian integer of width bytes.
+func appendArrowValue(buf []byte, d *Decimal, precision, scale int32, width int) ([]byte, error) {
+	if d.Form != Finite {
+		return buf, fmt.Errorf("%s is not finite", d.String())
+	}
+	// Fast path for values whose unscaled integer fits in 128 bits.
+	if u, ok := uint128FromBigInt(&d.Coeff); ok {
+		shift := int64(d.Exponent) + int64(scale)
+		if shift >= 0 && shift <= MaxDecimal128Digits {
+			u, ok = u.mulPow10(shift)
+		} else if shift < 0 && shift >= -MaxDecimal128Digits {
+			var r uint128
+			u, r = u.quoRem(pow10Uint128[-shift])
+			if !r.isZero() {
+				return buf, fmt.Errorf("%s cannot be represented with scale %d", d.String(), scale)
+			}
+		} else {
+			ok = false
+		}
+		if ok {
+			if precision <= MaxDecimal128Digits && u.cmp(pow10Uint128[precision]) >= 0 {
+				return buf, fmt.Errorf("%s has more than %d digits with scale %d", d.String(), precision, scale)
+			}
+			return appendArrowUint128(buf, u, d.Negative && !u.isZero(), width), nil
+		}
+	}
+
+	var x Decimal
+	if err := d.unscaled(&x, scale); err != nil {
+		return buf, err
+	}
+	if x.NumDigits() > int64(precision) {
+		return buf, fmt.Errorf("%s has more than %d digits with scale %d", d.String(), precision, scale)
+	}
+	// Write the big-endian two's-complement integer, sign-extend it to width
+	// bytes and reverse it.
+	var scratch [arrowDecimal256Width + 1]byte
+	be := appendTwosComplement(scratch[:0], &x.Coeff, x.Negative)
+	ext := byte(0)
+	if x.Negative {
+		ext = 0xff
+	}
+	for i := len(be) - 1; i >= 0; i-- {
+		buf = append(buf, be[i])
+	}
+	for i := len(be); i < width; i++ {
+		buf = append(buf, ext)
+	}
+	return buf, nil
+}
+
+// appendArrowUint128 appends the little-endian two's-complement integer of
+// width bytes with magnitude u and the given sign.
+func appendArrowUint128(buf []byte, u uint128, neg bool, width int) []byte {
+	ext := uint64(0)
+	if neg {
+		u = uint128{hi: ^u.hi, lo: ^u.lo}.add64(1)
+		ext = ^uint64(0)
+	}
+	var tmp [arrowDecimal256Width]byte
+	binary.LittleEndian.PutUint64(tmp[0:], u.lo)
+	binary.LittleEndian.PutUint64(tmp[8:], u.hi)
+	for i := arrowDecimal128Width; i < width; i += 8 {
+		binary.LittleEndian.PutUint64(tmp[i:], ext)
+	}
+	return append(buf, tmp[:width]...)
+}
+
+func decodeArrow(
+	dst []Decimal, src []byte, precision, scale int32, width int, maxPrecision int32,
+) error {
+	if err := checkArrowType(precision, scale, maxPrecision); err != nil {
+		return err
+	}
+	if len(src) != width*len(dst) {
+		return fmt.Errorf("arrow decimal: expected %d bytes for %d values, got %d", width*len(dst), len(dst), len(src))
+	}
+	var errs ElementErrors
+	for i := range dst {
+		if err := decodeArrowValue(&dst[i], src[i*width:(i+1)*width], precision, scale); err != nil {
+			errs = append(errs, ElementError{Index: i, Err: err})
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+var errArrowPrecision = errors.New("value exceeds the precision")
+
+// decodeArrowValue sets d to the little-endian integer b.
+func decodeArrowValue(d *Decimal, b []byte, precision, scale int32) error {
+	lo := binary.LittleEndian.Uint64(b)
+	hi := binary.LittleEndian.Uint64(b[8:])
+	neg := b[len(b)-1]&0x80 != 0
+	// Fast path for values that are sign-extended from 128 bits.
+	inline := true
+	for i := arrowDecimal128Width; i < len(b); i++ {
+		if b[i] != b[len(b)-1] {
+			inline = false
+			break
+		}
+	}
+	if inline && (hi>>63 != 0) == neg {
+		u := uint128{hi: hi, lo: lo}
+		if neg {
+			u = uint128{hi: ^u.hi, lo: ^u.lo}.add64(1)
+		}
+		if precision <= MaxDecimal128Digits && u.cmp(pow10Uint128[precision]) >= 0 {
+			return errArrowPrecision
+		}
+		u.bigInt(&d.Coeff)
+	} else {
+		var be [arrowDecimal256Width]byte
+		for i := range b {
+			be[len(b)-1-i] = b[i]
+		}
+		var x Decimal
+		if _, err := x.SetUnscaledBytes(be[:len(b)], 0); err != nil {
+			return err
+		}
+		if x.NumDigits() > int64(precision) {
+			return errArrowPrecision
+		}
+		d.Coeff.Set(&x.Coeff)
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = -scale
+	return nil
+}
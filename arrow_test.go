@@ -0,0 +1,206 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestArrowDecimal(t *testing.T) {
+	src := []Decimal{
+		*newDecimal(t, testCtx, "1.23"),
+		*newDecimal(t, testCtx, "-1.23"),
+		*newDecimal(t, testCtx, "-0"),
+		*newDecimal(t, testCtx, "1.2E+3"),
+		*newDecimal(t, testCtx, "-1.50000"),
+	}
+	expected := []string{
+		"7b000000000000000000000000000000",
+		"85ffffffffffffffffffffffffffffff",
+		"00000000000000000000000000000000",
+		"c0d40100000000000000000000000000",
+		"6affffffffffffffffffffffffffffff",
+	}
+	out := []string{"1.23", "-1.23", "0.00", "1200.00", "-1.50"}
+
+	b, err := AppendArrowDecimal128(nil, src, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, e := hex.EncodeToString(b), strings.Join(expected, ""); h != e {
+		t.Fatalf("expected %s, got %s", e, h)
+	}
+	dst := make([]Decimal, len(src))
+	if err := DecodeArrowDecimal128(dst, b, 10, 2); err != nil {
+		t.Fatal(err)
+	}
+	for i := range dst {
+		if s := dst[i].String(); s != out[i] {
+			t.Fatalf("%d: expected %s, got %s", i, out[i], s)
+		}
+	}
+
+	b, err = AppendArrowDecimal256(nil, src, 40, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range expected {
+		ext := "00"
+		if strings.HasSuffix(e, "ff") {
+			ext = "ff"
+		}
+		e += strings.Repeat(ext, 16)
+		if h := hex.EncodeToString(b[i*32 : (i+1)*32]); h != e {
+			t.Fatalf("%d: expected %s, got %s", i, e, h)
+		}
+	}
+	if err := DecodeArrowDecimal256(dst, b, 40, 2); err != nil {
+		t.Fatal(err)
+	}
+	for i := range dst {
+		if s := dst[i].String(); s != out[i] {
+			t.Fatalf("%d: expected %s, got %s", i, out[i], s)
+		}
+	}
+}
+
+// TestArrowDecimalRandom compares the Arrow encodings to the reversed
+// fixed-length unscaled bytes.
+func TestArrowDecimalRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	for _, tc := range []struct {
+		width     int
+		precision int32
+		encode    func([]byte, []Decimal, int32, int32) ([]byte, error)
+		decode    func([]Decimal, []byte, int32, int32) error
+	}{
+		{16, MaxArrowDecimal128Precision, AppendArrowDecimal128, DecodeArrowDecimal128},
+		{32, MaxArrowDecimal256Precision, AppendArrowDecimal256, DecodeArrowDecimal256},
+	} {
+		src := make([]Decimal, 2000)
+		scale := int32(rng.Intn(10))
+		for i := range src {
+			var buf strings.Builder
+			if rng.Intn(2) == 0 {
+				buf.WriteByte('-')
+			}
+			n := 1 + rng.Intn(int(tc.precision-scale))
+			for j := 0; j < n; j++ {
+				buf.WriteByte(byte('0' + rng.Intn(10)))
+			}
+			fmt.Fprintf(&buf, "E%d", -rng.Intn(int(scale)+1))
+			src[i] = *newDecimal(t, testCtx, buf.String())
+		}
+		b, err := tc.encode(nil, src, tc.precision, scale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range src {
+			be, err := src[i].AppendUnscaledBytesFixed(nil, scale, tc.width)
+			if err != nil {
+				t.Fatal(err)
+			}
+			le := b[i*tc.width : (i+1)*tc.width]
+			for j := range be {
+				if be[j] != le[len(le)-1-j] {
+					t.Fatalf("%s: expected reversed %x, got %x", &src[i], be, le)
+				}
+			}
+		}
+		dst := make([]Decimal, len(src))
+		if err := tc.decode(dst, b, tc.precision, scale); err != nil {
+			t.Fatal(err)
+		}
+		for i := range dst {
+			if dst[i].Cmp(&src[i]) != 0 || dst[i].Exponent != -scale {
+				t.Fatalf("expected %s, got %s", &src[i], &dst[i])
+			}
+		}
+	}
+}
+
+func TestArrowDecimalErrors(t *testing.T) {
+	src := []Decimal{
+		*newDecimal(t, testCtx, "1"),
+		*newDecimal(t, testCtx, "NaN"),
+		*newDecimal(t, testCtx, "1.234"),
+		*newDecimal(t, testCtx, "100000"),
+		*newDecimal(t, testCtx, "-99999.99"),
+		*newDecimal(t, testCtx, "1E+50"),
+		*newDecimal(t, testCtx, "1234567890123456789012345678901234567890E-40"),
+	}
+	b, err := AppendArrowDecimal128(nil, src, 7, 2)
+	var errs ElementErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ElementErrors, got %v", err)
+	}
+	var idx []int
+	for _, e := range errs {
+		idx = append(idx, e.Index)
+	}
+	if fmt.Sprint(idx) != "[1 2 3 5 6]" {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(b) != 16*len(src) || !bytes.Equal(b[16:32], make([]byte, 16)) {
+		t.Fatalf("expected zeros for failed elements: %x", b)
+	}
+
+	// Decoding checks the precision.
+	dst := make([]Decimal, len(src))
+	err = DecodeArrowDecimal128(dst, b, 5, 2)
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Index != 4 {
+		t.Fatalf("unexpected errors: %v", err)
+	}
+	b, _ = AppendArrowDecimal256(nil, []Decimal{*newDecimal(t, testCtx, "1E+70")}, 76, 0)
+	if err := DecodeArrowDecimal256(dst[:1], b, 70, 0); !errors.As(err, &errs) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Invalid types and lengths.
+	if _, err := AppendArrowDecimal128(nil, src, 39, 0); err == nil || errors.As(err, &errs) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := DecodeArrowDecimal256(dst, b, 0, 0); err == nil || errors.As(err, &errs) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := DecodeArrowDecimal128(dst, b, 10, 0); err == nil || errors.As(err, &errs) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestArrowDecimalAllocs(t *testing.T) {
+	src := []Decimal{
+		*New(12345, -2),
+		*New(-1, 0),
+		*newDecimal(t, testCtx, "1234567890123456789012345678.90"),
+	}
+	buf := make([]byte, 0, 32*len(src))
+	dst := make([]Decimal, len(src))
+	allocs := testing.AllocsPerRun(100, func() {
+		b, _ := AppendArrowDecimal128(buf, src, 38, 4)
+		_ = DecodeArrowDecimal128(dst, b, 38, 4)
+		b, _ = AppendArrowDecimal256(buf, src, 76, 4)
+		_ = DecodeArrowDecimal256(dst, b, 76, 4)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
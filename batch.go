@@ -0,0 +1,266 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// The slice operations below apply an operation to each element of their
+// operands. They compute the same results as the corresponding Context
+// methods, but elements with coefficients that fit in 64 bits and exact
+// results are computed against bounds derived from c once per call, and traps
+// are only converted to errors for elements that trigger them. The returned
+// Condition is the union of the Conditions of all elements. Elements whose
+// Condition matches c.Traps, or whose operation fails, are reported in the
+// returned ElementErrors; the other elements of dst are still set. dst may be
+// the same slice as an operand, but a scalar operand must not be an element
+// of dst.
+
+// AddSlices sets dst[i] to the sum xs[i]+ys[i]. dst, xs and ys must have
+// the same length.
+func (c *Context) AddSlices(dst, xs, ys []Decimal) (Condition, error) {
+	if err := checkSliceLengths("AddSlices", dst, xs, ys); err != nil {
+		return 0, err
+	}
+	return c.binarySlices(dst, xs, ys, nil, batchAdd)
+}
+
+// SubSlices sets dst[i] to the difference xs[i]-ys[i]. dst, xs and ys must
+// have the same length.
+func (c *Context) SubSlices(dst, xs, ys []Decimal) (Condition, error) {
+	if err := checkSliceLengths("SubSlices", dst, xs, ys); err != nil {
+		return 0, err
+	}
+	return c.binarySlices(dst, xs, ys, nil, batchSub)
+}
+
+// MulSlices sets dst[i] to the product xs[i]*ys[i]. dst, xs and ys must have
+// the same length.
+func (c *Context) MulSlices(dst, xs, ys []Decimal) (Condition, error) {
+	if err := checkSliceLengths("MulSlices", dst, xs, ys); err != nil {
+		return 0, err
+	}
+	return c.binarySlices(dst, xs, ys, nil, batchMul)
+}
+
+// AddScalar sets dst[i] to the sum xs[i]+y. dst and xs must have the same
+// length.
+func (c *Context) AddScalar(dst, xs []Decimal, y *Decimal) (Condition, error) {
+	if err := checkSliceLengths("AddScalar", dst, xs, xs); err != nil {
+		return 0, err
+	}
+	return c.binarySlices(dst, xs, nil, y, batchAdd)
+}
+
+// MulScalar sets dst[i] to the product xs[i]*y. dst and xs must have the
+// same length.
+func (c *Context) MulScalar(dst, xs []Decimal, y *Decimal) (Condition, error) {
+	if err := checkSliceLengths("MulScalar", dst, xs, xs); err != nil {
+		return 0, err
+	}
+	return c.binarySlices(dst, xs, nil, y, batchMul)
+}
+
+// CmpSlices sets dst[i] to xs[i].Cmp(&ys[i]). dst, xs and ys must have the
+// same length.
+func CmpSlices(dst []int, xs, ys []Decimal) error {
+	if len(xs) != len(dst) || len(ys) != len(dst) {
+		return fmt.Errorf("CmpSlices: mismatched lengths %d, %d and %d", len(dst), len(xs), len(ys))
+	}
+	for i := range dst {
+		dst[i] = xs[i].Cmp(&ys[i])
+	}
+	return nil
+}
+
+func checkSliceLengths(op string, dst, xs, ys []Decimal) error {
+	if len(xs) != len(dst) || len(ys) != len(dst) {
+		return fmt.Errorf("%s: mismatched lengths %d, %d and %d", op, len(dst), len(xs), len(ys))
+	}
+	return nil
+}
+
+type batchOp int
+
+const (
+	batchAdd batchOp = iota
+	batchSub
+	batchMul
+)
+
+// binarySlices sets dst[i] to op applied to xs[i] and either ys[i] or, if
+// ys is nil, y.
+func (c *Context) binarySlices(dst, xs, ys []Decimal, y *Decimal, op batchOp) (Condition, error) {
+	// Elements that are not computed by the fast path use a Context without
+	// traps, so that only the Conditions of elements that trap are converted
+	// to errors.
+	nc := *c
+	nc.Traps = 0
+	k := newSliceKernel(c)
+	var yu uint64
+	yok := false
+	if ys == nil {
+		yu, yok = uint64Operand(y)
+	}
+	var all Condition
+	var errs ElementErrors
+	slow := func(i int, x, y *Decimal) {
+		var res Condition
+		var err error
+		switch op {
+		case batchAdd:
+			res, err = nc.add(&dst[i], x, y, false)
+		case batchSub:
+			res, err = nc.add(&dst[i], x, y, true)
+		case batchMul:
+			res, err = nc.Mul(&dst[i], x, y)
+		}
+		all |= res
+		if err == nil && res&c.Traps != 0 {
+			_, err = c.goError(res)
+		}
+		if err != nil {
+			errs = append(errs, ElementError{Index: i, Err: err})
+		}
+	}
+	// The loops are split by op so that the fast path of each element does
+	// not dispatch on it.
+	if op == batchMul {
+		for i := range dst {
+			x := &xs[i]
+			if ys != nil {
+				y = &ys[i]
+				yu, yok = uint64Operand(y)
+			}
+			if xu, ok := uint64Operand(x); !ok || !yok || !k.mul(&dst[i], x, xu, y, yu) {
+				slow(i, x, y)
+			}
+		}
+	} else {
+		subtract := op == batchSub
+		for i := range dst {
+			x := &xs[i]
+			if ys != nil {
+				y = &ys[i]
+				yu, yok = uint64Operand(y)
+			}
+			if xu, ok := uint64Operand(x); !ok || !yok || !k.add(&dst[i], x, xu, y, yu, subtract) {
+				slow(i, x, y)
+			}
+		}
+	}
+	if errs != nil {
+		return all, errs
+	}
+	return all, nil
+}
+
+// sliceKernel computes the elements of the slice operations whose
+// coefficients fit in 64 bits and whose exact results need neither rounding
+// nor any check of the exponent beyond a range that is computed once from the
+// Context. Such results have no Condition.
+type sliceKernel struct {
+	// limit bounds the coefficients of the results, unless unlimited.
+	limit     uint128
+	unlimited bool
+	// minExp and maxExp bound the exponents of results that are normal with
+	// any number of digits up to MaxDecimal128Digits+1.
+	minExp, maxExp int64
+	// negZero is the sign of an exact cancellation.
+	negZero bool
+}
+
+func newSliceKernel(c *Context) sliceKernel {
+	k := sliceKernel{
+		minExp:  MinExponent,
+		maxExp:  MaxExponent - MaxDecimal128Digits,
+		negZero: c.Rounding == RoundFloor,
+	}
+	if e := int64(c.MinExponent); e > k.minExp {
+		k.minExp = e
+	}
+	if e := int64(c.MaxExponent) - MaxDecimal128Digits; e < k.maxExp {
+		k.maxExp = e
+	}
+	if c.Precision == 0 || c.Precision > MaxDecimal128Digits {
+		k.unlimited = true
+	} else {
+		k.limit = pow10Uint128[c.Precision]
+	}
+	return k
+}
+
+// uint64Operand returns the coefficient of d if d is finite and its
+// coefficient fits in 64 bits.
+func uint64Operand(d *Decimal) (uint64, bool) {
+	if d.Form != Finite {
+		return 0, false
+	}
+	u, _, ok := d.Coeff.innerAsUint64()
+	return u, ok
+}
+
+// add sets d to x+y, or x-y if subtract is true, where xu and yu are the
+// coefficients of x and y. It returns false without modifying d if the result
+// is not exact or not normal.
+func (k *sliceKernel) add(d, x *Decimal, xu uint64, y *Decimal, yu uint64, subtract bool) bool {
+	xn, yn := x.Negative, y.Negative != subtract
+	a, b := uint128{lo: xu}, uint128{lo: yu}
+	exp := int64(x.Exponent)
+	// Scaling by at most 10^19 keeps both coefficients below 2^127.
+	switch s := exp - int64(y.Exponent); {
+	case s > 0 && s < 20:
+		a, _ = a.mul64(pow10Uint128[s].lo)
+		exp = int64(y.Exponent)
+	case s < 0 && s > -20:
+		b, _ = b.mul64(pow10Uint128[-s].lo)
+	case s != 0:
+		return false
+	}
+	r := a
+	neg := xn
+	if xn == yn {
+		r, _ = a.add(b)
+	} else if a.cmp(b) >= 0 {
+		r = a.sub(b)
+	} else {
+		r = b.sub(a)
+		neg = !neg
+	}
+	if r.isZero() && xn != yn {
+		neg = k.negZero
+	}
+	return k.set(d, r, exp, neg)
+}
+
+// mul sets d to x*y, where xu and yu are the coefficients of x and y. It
+// returns false without modifying d if the result is not exact or not normal.
+func (k *sliceKernel) mul(d, x *Decimal, xu uint64, y *Decimal, yu uint64) bool {
+	hi, lo := bits.Mul64(xu, yu)
+	return k.set(d, uint128{hi, lo}, int64(x.Exponent)+int64(y.Exponent), x.Negative != y.Negative)
+}
+
+func (k *sliceKernel) set(d *Decimal, r uint128, exp int64, neg bool) bool {
+	if exp < k.minExp || exp > k.maxExp || !k.unlimited && r.cmp(k.limit) >= 0 {
+		return false
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = int32(exp)
+	r.bigInt(&d.Coeff)
+	return true
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"errors"
+	"testing"
+)
+
+var batchOperands = []string{
+	"0", "-0", "1", "-1.5", "123.456", "9.99999", "1E+5", "-7E-3",
+	"99999999999999999999999999999999999999", "1E+100", "1E-100",
+	"123456789012345678901234567890123456789012", "NaN", "Infinity", "-Infinity",
+}
+
+func TestBatch(t *testing.T) {
+	ctx := BaseContext.WithPrecision(10)
+	ctx.Traps = Overflow | InvalidOperation
+	ctx.MaxExponent = 150
+	ctx.MinExponent = -150
+	var xs, ys []Decimal
+	for _, a := range batchOperands {
+		for _, b := range batchOperands {
+			xs = append(xs, *newDecimal(t, testCtx, a))
+			ys = append(ys, *newDecimal(t, testCtx, b))
+		}
+	}
+	tests := []struct {
+		name  string
+		batch func(dst []Decimal) (Condition, error)
+		op    func(d, x, y *Decimal) (Condition, error)
+	}{
+		{"AddSlices", func(dst []Decimal) (Condition, error) { return ctx.AddSlices(dst, xs, ys) }, ctx.Add},
+		{"SubSlices", func(dst []Decimal) (Condition, error) { return ctx.SubSlices(dst, xs, ys) }, ctx.Sub},
+		{"MulSlices", func(dst []Decimal) (Condition, error) { return ctx.MulSlices(dst, xs, ys) }, ctx.Mul},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := make([]Decimal, len(xs))
+			res, err := tc.batch(dst)
+			var errs ElementErrors
+			if err != nil && !errors.As(err, &errs) {
+				t.Fatalf("expected ElementErrors, got %v", err)
+			}
+			var wantRes Condition
+			for i := range xs {
+				var d Decimal
+				r, err := tc.op(&d, &xs[i], &ys[i])
+				wantRes |= r
+				if d.String() != dst[i].String() {
+					t.Errorf("%s, %s: got %s, expected %s", &xs[i], &ys[i], &dst[i], &d)
+				}
+				if err != nil {
+					if len(errs) == 0 || errs[0].Index != i || errs[0].Err.Error() != err.Error() {
+						t.Errorf("%s, %s: expected error %v at %d, got %v", &xs[i], &ys[i], err, i, errs)
+					}
+					errs = errs[1:]
+				}
+			}
+			if len(errs) != 0 {
+				t.Errorf("unexpected errors %v", errs)
+			}
+			if res != wantRes {
+				t.Errorf("got condition %s, expected %s", res, wantRes)
+			}
+		})
+	}
+
+	// The scalar variants match the slice variants with a repeated operand.
+	y := newDecimal(t, testCtx, "-2.5")
+	ys = make([]Decimal, len(xs))
+	for i := range ys {
+		ys[i].Set(y)
+	}
+	for _, tc := range []struct {
+		name   string
+		scalar func(dst []Decimal) (Condition, error)
+		slices func(dst []Decimal) (Condition, error)
+	}{
+		{"AddScalar",
+			func(dst []Decimal) (Condition, error) { return ctx.AddScalar(dst, xs, y) },
+			func(dst []Decimal) (Condition, error) { return ctx.AddSlices(dst, xs, ys) }},
+		{"MulScalar",
+			func(dst []Decimal) (Condition, error) { return ctx.MulScalar(dst, xs, y) },
+			func(dst []Decimal) (Condition, error) { return ctx.MulSlices(dst, xs, ys) }},
+	} {
+		got, want := make([]Decimal, len(xs)), make([]Decimal, len(xs))
+		res, err := tc.scalar(got)
+		wantRes, wantErr := tc.slices(want)
+		if res != wantRes || (err == nil) != (wantErr == nil) {
+			t.Errorf("%s: got %s, %v, expected %s, %v", tc.name, res, err, wantRes, wantErr)
+		}
+		for i := range got {
+			if got[i].String() != want[i].String() {
+				t.Errorf("%s: %s: got %s, expected %s", tc.name, &xs[i], &got[i], &want[i])
+			}
+		}
+	}
+
+	// dst may be an operand.
+	dst := make([]Decimal, len(xs))
+	for i := range dst {
+		dst[i].Set(&xs[i])
+	}
+	if _, err := ctx.MulScalar(dst, dst, y); err != nil {
+		t.Fatal(err)
+	}
+	for i := range dst {
+		var d Decimal
+		_, _ = ctx.Mul(&d, &xs[i], y)
+		if d.String() != dst[i].String() {
+			t.Errorf("in place: %s: got %s, expected %s", &xs[i], &dst[i], &d)
+		}
+	}
+
+	cmps := make([]int, len(xs))
+	if err := CmpSlices(cmps, xs, ys); err != nil {
+		t.Fatal(err)
+	}
+	for i := range cmps {
+		if want := xs[i].Cmp(&ys[i]); cmps[i] != want {
+			t.Errorf("CmpSlices: %s, %s: got %d, expected %d", &xs[i], &ys[i], cmps[i], want)
+		}
+	}
+
+	if _, err := ctx.AddSlices(make([]Decimal, 1), xs, ys); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+	if err := CmpSlices(cmps[:1], xs, ys); err == nil {
+		t.Error("expected error for mismatched lengths")
+	}
+}
+
+// TestBatchFastPath checks that the elements computed in 64 bits match the
+// Context methods around the bounds of precision and exponent at which the
+// fast path gives way to them.
+func TestBatchFastPath(t *testing.T) {
+	operands := []string{
+		"0", "-0", "0E+145", "5", "-25E-2", "18446744073709551615", "-18446744073709551616",
+		"9999999999", "1E+9", "12345E+110", "1E-149", "7E+19", "-7E-19", "3E-20",
+	}
+	var xs, ys []Decimal
+	for _, a := range operands {
+		for _, b := range operands {
+			xs = append(xs, *newDecimal(t, testCtx, a))
+			ys = append(ys, *newDecimal(t, testCtx, b))
+		}
+	}
+	for _, prec := range []uint32{0, 1, 10, 20, 38, 39} {
+		for _, rounding := range []Rounder{RoundHalfEven, RoundFloor} {
+			ctx := BaseContext.WithPrecision(prec)
+			ctx.Rounding = rounding
+			ctx.Traps = 0
+			ctx.MaxExponent = 150
+			ctx.MinExponent = -150
+			for _, tc := range []struct {
+				name  string
+				batch func(c *Context, dst, xs, ys []Decimal) (Condition, error)
+				op    func(c *Context, d, x, y *Decimal) (Condition, error)
+			}{
+				{"AddSlices", (*Context).AddSlices, (*Context).Add},
+				{"SubSlices", (*Context).SubSlices, (*Context).Sub},
+				{"MulSlices", (*Context).MulSlices, (*Context).Mul},
+			} {
+				dst := make([]Decimal, len(xs))
+				res, err := tc.batch(ctx, dst, xs, ys)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var wantRes Condition
+				for i := range xs {
+					var d Decimal
+					r, err := tc.op(ctx, &d, &xs[i], &ys[i])
+					if err != nil {
+						t.Fatal(err)
+					}
+					wantRes |= r
+					if d.String() != dst[i].String() {
+						t.Errorf("%s %d %s: %s, %s: got %s, expected %s", tc.name, prec, rounding, &xs[i], &ys[i], &dst[i], &d)
+					}
+					if one, _ := tc.batch(ctx, dst[i:i+1], xs[i:i+1], ys[i:i+1]); one != r {
+						t.Errorf("%s %d %s: %s, %s: got condition %s, expected %s", tc.name, prec, rounding, &xs[i], &ys[i], one, r)
+					}
+				}
+				if res != wantRes {
+					t.Errorf("%s %d %s: got condition %s, expected %s", tc.name, prec, rounding, res, wantRes)
+				}
+			}
+		}
+	}
+}
@@ -76,6 +76,18 @@ func runBenches(
 	}
 }
 
+// runWorkspaceBenches is like runBenches, but the Context passed to fn has a
+// Workspace.
+func runWorkspaceBenches(
+	b *testing.B, precision, inScale, inNumDigits []int, fn func(*testing.B, *Context, *Decimal),
+) {
+	ws := new(Workspace)
+	runBenches(b, precision, inScale, inNumDigits, func(b *testing.B, ctx *Context, x *Decimal) {
+		ctx.Workspace = ws
+		fn(b, ctx, x)
+	})
+}
+
 func BenchmarkExp(b *testing.B) {
 	precision := []int{5, 10, 100}
 	scale := []int{-4, -1, 2}
@@ -104,6 +116,34 @@ func BenchmarkLn(b *testing.B) {
 	)
 }
 
+func BenchmarkExpWorkspace(b *testing.B) {
+	precision := []int{5, 10, 100}
+	scale := []int{-4, -1, 2}
+	digits := []int{-100, -10, -2, 2, 10, 100}
+	runWorkspaceBenches(
+		b, precision, scale, digits,
+		func(b *testing.B, ctx *Context, x *Decimal) {
+			if _, err := ctx.Exp(&Decimal{}, x); err != nil {
+				b.Fatal(err)
+			}
+		},
+	)
+}
+
+func BenchmarkLnWorkspace(b *testing.B) {
+	precision := []int{2, 10, 100}
+	scale := []int{-100, -10, -2, 2, 10, 100}
+	digits := []int{2, 10, 100}
+	runWorkspaceBenches(
+		b, precision, scale, digits,
+		func(b *testing.B, ctx *Context, x *Decimal) {
+			if _, err := ctx.Ln(&Decimal{}, x); err != nil {
+				b.Fatal(err)
+			}
+		},
+	)
+}
+
 func BenchmarkDecimalString(b *testing.B) {
 	rng := rand.New(rand.NewSource(461210934723948))
 	corpus := func() []Decimal {
@@ -142,3 +182,141 @@ func BenchmarkDecimalSetFloat(b *testing.B) {
 		}
 	}
 }
+
+// smallOperands are money-like values whose coefficients, and the
+// coefficients aligned to a common exponent, fit in 64 or 128 bits.
+var smallOperands = [][2]string{
+	{"1234.56", "78.9"},
+	{"-0.01", "19.99"},
+	{"123456789012.34", "0.000001"},
+	{"99999999999999999.99", "1E+5"},
+}
+
+func benchmarkSmallBinaryOp(b *testing.B, op func(c *Context, d, x, y *Decimal) (Condition, error)) {
+	ctx := BaseContext.WithPrecision(34)
+	for _, ops := range smallOperands {
+		var x, y, d Decimal
+		if _, _, err := x.SetString(ops[0]); err != nil {
+			b.Fatal(err)
+		}
+		if _, _, err := y.SetString(ops[1]); err != nil {
+			b.Fatal(err)
+		}
+		b.Run(ops[0]+"/"+ops[1], func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := op(ctx, &d, &x, &y); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAddSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, (*Context).Add)
+}
+
+func BenchmarkSubSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, (*Context).Sub)
+}
+
+func BenchmarkMulSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, (*Context).Mul)
+}
+
+func BenchmarkCmpSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, func(_ *Context, _, x, y *Decimal) (Condition, error) {
+		_ = x.Cmp(y)
+		return 0, nil
+	})
+}
+
+func BenchmarkQuoSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, (*Context).Quo)
+}
+
+func BenchmarkQuoIntegerSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, (*Context).QuoInteger)
+}
+
+func BenchmarkRemSmall(b *testing.B) {
+	benchmarkSmallBinaryOp(b, (*Context).Rem)
+}
+
+func benchmarkDigits(b *testing.B, f func(b *testing.B, s string)) {
+	rng := rand.New(rand.NewSource(461210934723948))
+	for _, n := range []int{1e4, 1e5, 1e6, 1e7} {
+		s := randDigits(rng, n)
+		b.Run(fmt.Sprintf("digits=%d", n), func(b *testing.B) {
+			f(b, s)
+		})
+	}
+}
+
+func BenchmarkDecimalSetStringLarge(b *testing.B) {
+	benchmarkDigits(b, func(b *testing.B, s string) {
+		var d Decimal
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := d.SetString(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDecimalStringLarge(b *testing.B) {
+	benchmarkDigits(b, func(b *testing.B, s string) {
+		d, _, err := NewFromString(s)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = d.String()
+		}
+	})
+}
+
+func benchmarkSlices(
+	b *testing.B,
+	batch func(c *Context, dst, xs, ys []Decimal) (Condition, error),
+	op func(c *Context, d, x, y *Decimal) (Condition, error),
+) {
+	ctx := BaseContext.WithPrecision(34)
+	rng := rand.New(rand.NewSource(461210934723948))
+	const n = 1024
+	xs, ys, dst := make([]Decimal, n), make([]Decimal, n), make([]Decimal, n)
+	for i := range xs {
+		xs[i].SetFinite(rng.Int63n(1e12), -int32(rng.Intn(6)))
+		ys[i].SetFinite(rng.Int63n(1e12), -int32(rng.Intn(6)))
+	}
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := batch(ctx, dst, xs, ys); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := range dst {
+				if _, err := op(ctx, &dst[j], &xs[j], &ys[j]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkAddSlices(b *testing.B) {
+	benchmarkSlices(b, (*Context).AddSlices, (*Context).Add)
+}
+
+func BenchmarkMulSlices(b *testing.B) {
+	benchmarkSlices(b, (*Context).MulSlices, (*Context).Mul)
+}
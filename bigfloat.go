@@ -0,0 +1,220 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// SetBigFloat sets d to the exact value of x and returns d. Every binary
+// floating-point value has a finite decimal expansion, but one with a binary
+// exponent of -n has n digits after the decimal point, so an error is returned
+// if the decimal exponent does not fit in an int32.
+func (d *Decimal) SetBigFloat(x *big.Float) (*Decimal, error) {
+	if x.IsInf() {
+		return d.setSpecial(Infinite, x.Signbit()), nil
+	}
+	if x.Sign() == 0 {
+		return d.setSpecial(Finite, x.Signbit()), nil
+	}
+	// x is m × 2**exp with an odd integer m of prec bits.
+	prec := int64(x.MinPrec())
+	exp := int64(x.MantExp(nil)) - prec
+	if exp < math.MinInt32 {
+		return nil, fmt.Errorf("%s: %s", x.Text('p', 0), errExponentOutOfRangeStr)
+	}
+	var mf big.Float
+	var m big.Int
+	mf.SetMantExp(x, int(-exp)).Int(&m)
+	neg := m.Sign() < 0
+	m.Abs(&m)
+	if m.IsUint64() {
+		d.setBinary(m.Uint64(), int(exp), neg)
+		return d, nil
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = 0
+	d.Coeff.SetMathBigInt(&m)
+	if exp >= 0 {
+		d.Coeff.Lsh(&d.Coeff, uint(exp))
+		return d, nil
+	}
+	// m × 2**exp is m × 5**-exp × 10**exp.
+	var p BigInt
+	p.Exp(bigFive, NewBigInt(-exp), nil)
+	d.Coeff.Mul(&d.Coeff, &p)
+	d.Exponent = int32(exp)
+	return d, nil
+}
+
+// maxBigFloatAdj and minBigFloatAdj bound the adjusted exponents of decimals
+// within the range of big.Float, whose largest values are below
+// 2**big.MaxExp, about 10**646456993, and whose smallest are
+// 2**(big.MinExp-1), about 10**-646456994.
+const (
+	maxBigFloatAdj = 646456993
+	minBigFloatAdj = -646456995
+)
+
+// BigFloat returns d correctly rounded to a big.Float with precision prec and
+// rounding mode mode. The Acc method of the result reports whether it is
+// exact. As with big.Float.SetRat, a prec of 0 is the largest of 64 and the
+// bit lengths of the coefficient and of the power of ten. An error is returned
+// if d is NaN.
+func (d *Decimal) BigFloat(prec uint, mode big.RoundingMode) (*big.Float, error) {
+	z := new(big.Float).SetPrec(prec).SetMode(mode)
+	switch d.Form {
+	case NaN, NaNSignaling:
+		return nil, fmt.Errorf("%s is not a number", d.String())
+	case Infinite:
+		return z.SetInf(d.Negative), nil
+	}
+	if d.IsZero() {
+		if d.Negative {
+			return z.Neg(z.SetInt64(0)), nil
+		}
+		return z.SetInt64(0), nil
+	}
+	// Beyond the exponent range of big.Float, d overflows to ±Inf or
+	// underflows to ±0 as in big.Float arithmetic, which also sets the
+	// accuracy.
+	switch adj := int64(d.Exponent) + d.NumDigits() - 1; {
+	case adj > maxBigFloatAdj:
+		return setBigFloatExp(z, d.Negative, big.MaxExp), nil
+	case adj < minBigFloatAdj:
+		return setBigFloatExp(z, d.Negative, big.MinExp-2), nil
+	}
+	var tmp big.Int
+	c := d.Coeff.inner(&tmp)
+	// Unless the result can be exact, approximate it at a working precision
+	// bounded by prec instead of computing the power of ten exactly. A
+	// positive exponent leaves 5**exp > 2**prec in the odd part of the
+	// value, and a negative one divides by 5**-exp > c.
+	if exp := int64(d.Exponent); prec > 0 && (2*exp > int64(prec) || -2*exp > int64(c.BitLen())) {
+		if roundBigFloat(z, c, d.Negative, exp) {
+			return z, nil
+		}
+	}
+	// Round once, from exact operands with the sign of d.
+	var x big.Float
+	if d.Exponent >= 0 {
+		var n big.Int
+		x.SetInt(n.Mul(c, bigIntPow10(int64(d.Exponent))))
+		if d.Negative {
+			x.Neg(&x)
+		}
+		z.Set(&x)
+	} else {
+		// The quotient of two exact values is correctly rounded.
+		var y big.Float
+		x.SetInt(c)
+		if d.Negative {
+			x.Neg(&x)
+		}
+		y.SetInt(bigIntPow10(-int64(d.Exponent)))
+		z.Quo(&x, &y)
+	}
+	return z, nil
+}
+
+// setBigFloatExp sets z to ±2**exp with the precision and rounding mode of z,
+// which overflows or underflows for exponents outside of the range of
+// big.Float.
+func setBigFloatExp(z *big.Float, neg bool, exp int) *big.Float {
+	var one big.Float
+	one.SetPrec(z.Prec()).SetMode(z.Mode()).SetInt64(1)
+	if neg {
+		one.Neg(&one)
+	}
+	return z.SetMantExp(&one, exp)
+}
+
+// roundBigFloat sets z to c × 10**exp, negated if neg, correctly rounded to
+// the precision and rounding mode of z, where the value is known to be
+// inexact. It computes c × 5**exp with a bounded relative error at increasing
+// working precisions until the rounding of the whole error interval, and its
+// direction, is determined, then scales it exactly by 2**exp. It returns false
+// if the working precision would exceed that of the exact value.
+func roundBigFloat(z *big.Float, c *big.Int, neg bool, exp int64) bool {
+	n := uint64(exp)
+	if exp < 0 {
+		n = uint64(-exp)
+	}
+	// Computing 5**n by squaring rounds at most 2*bits.Len64(n) times, and
+	// the relative errors of the powers grow to less than 2n rounding
+	// errors, with two more for c and the final product or quotient.
+	guard := uint(bits.Len64(n)) + 4
+	maxPrec := uint(float64(n)*math.Log2(10)) + uint(c.BitLen())
+	for w := z.Prec() + 64 + guard; w < maxPrec; w *= 2 {
+		var x, p big.Float
+		x.SetPrec(w).SetInt(c)
+		if neg {
+			x.Neg(&x)
+		}
+		pow5BigFloat(&p, n, w)
+		if exp >= 0 {
+			x.Mul(&x, &p)
+		} else {
+			x.Quo(&x, &p)
+		}
+		var eps, lo, hi big.Float
+		eps.SetMantExp(&x, int(guard)-int(w)).Abs(&eps)
+		lo.SetPrec(w).SetMode(big.ToNegativeInf).Sub(&x, &eps)
+		hi.SetPrec(w).SetMode(big.ToPositiveInf).Add(&x, &eps)
+		loExp := int64(lo.MantExp(nil)) + exp
+		hiExp := int64(hi.MantExp(nil)) + exp
+		switch {
+		case loExp > big.MaxExp && hiExp > big.MaxExp:
+			setBigFloatExp(z, neg, big.MaxExp)
+			return true
+		case loExp < big.MinExp && hiExp < big.MinExp:
+			setBigFloatExp(z, neg, big.MinExp-2)
+			return true
+		case loExp > big.MaxExp || hiExp > big.MaxExp || loExp < big.MinExp || hiExp < big.MinExp:
+			continue
+		}
+		lo.SetMantExp(&lo, int(exp))
+		hi.SetMantExp(&hi, int(exp))
+		// The value lies strictly between lo and hi, so if both round to
+		// the same result outside of [lo, hi], so does the value, and
+		// rounding lo gives the accuracy of the result.
+		var r big.Float
+		r.SetPrec(z.Prec()).SetMode(z.Mode()).Set(&hi)
+		z.Set(&lo)
+		if z.Cmp(&r) == 0 && (z.Cmp(&lo) < 0 || z.Cmp(&hi) > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// pow5BigFloat sets z to 5**n computed by squaring at precision prec.
+func pow5BigFloat(z *big.Float, n uint64, prec uint) {
+	var b big.Float
+	b.SetPrec(prec).SetInt64(5)
+	z.SetPrec(prec).SetInt64(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			z.Mul(z, &b)
+		}
+		if n > 1 {
+			b.Mul(&b, &b)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestSetBigFloat(t *testing.T) {
+	tests := []struct {
+		x        *big.Float
+		expected string
+	}{
+		{x: big.NewFloat(0), expected: "0"},
+		{x: big.NewFloat(math.Copysign(0, -1)), expected: "-0"},
+		{x: big.NewFloat(0.1), expected: "0.1000000000000000055511151231257827021181583404541015625"},
+		{x: big.NewFloat(-3), expected: "-3"},
+		{x: new(big.Float).SetInf(true), expected: "-Infinity"},
+		{x: new(big.Float).SetMantExp(big.NewFloat(1), 100), expected: "1267650600228229401496703205376"},
+		{x: new(big.Float).SetMantExp(big.NewFloat(1), -20), expected: "9.5367431640625E-7"},
+	}
+	for _, tc := range tests {
+		var d Decimal
+		if _, err := d.SetBigFloat(tc.x); err != nil {
+			t.Fatal(err)
+		}
+		if s := d.String(); s != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.x.Text('p', 0), tc.expected, s)
+		}
+	}
+
+	// Compare against big.Float.Rat, which is also exact.
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		prec := uint(rnd.Intn(300) + 1)
+		m := new(big.Int).Rand(rnd, new(big.Int).Lsh(bigIntOne, prec))
+		if rnd.Intn(2) == 0 {
+			m.Neg(m)
+		}
+		x := new(big.Float).SetPrec(prec).SetInt(m)
+		x.SetMantExp(x, rnd.Intn(2000)-1000)
+		var d Decimal
+		if _, err := d.SetBigFloat(x); err != nil {
+			t.Fatal(err)
+		}
+		r, err := d.Rat(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := x.Rat(nil)
+		if r.Cmp(expected) != 0 {
+			t.Fatalf("%s: inexact result %s", x.Text('p', 0), &d)
+		}
+	}
+
+	// An odd 100-bit mantissa with an exponent of math.MinInt32-50.
+	m := new(big.Int).Sub(new(big.Int).Lsh(bigIntOne, 100), bigIntOne)
+	x := new(big.Float).SetInt(m)
+	x.SetMantExp(x, math.MinInt32-50)
+	if _, err := new(Decimal).SetBigFloat(x); err == nil {
+		t.Fatal("expected exponent out of range error")
+	}
+}
+
+var bigFloatModes = []big.RoundingMode{
+	big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf,
+}
+
+func TestBigFloat(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inputs := []string{"0", "-0", "1", "0.1", "-0.5", "2.5", "1E+400", "-1E-400", "123456789012345678901234567890", "1E+100000", "-0E+900000000"}
+	for i := 0; i < 500; i++ {
+		digits := rnd.Intn(60) + 1
+		coeff := make([]byte, digits)
+		for j := range coeff {
+			coeff[j] = byte('0' + rnd.Intn(10))
+		}
+		inputs = append(inputs, fmt.Sprintf("%s%sE%d", []string{"", "-"}[rnd.Intn(2)], coeff, rnd.Intn(800)-400))
+	}
+	for _, s := range inputs {
+		d := newDecimal(t, testCtx, s)
+		r, err := d.Rat(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, prec := range []uint{1, 24, 53, 64, 200} {
+			for _, mode := range bigFloatModes {
+				f, err := d.BigFloat(prec, mode)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected := new(big.Float).SetPrec(prec).SetMode(mode).SetRat(r)
+				if d.Negative && d.IsZero() {
+					expected.Neg(expected)
+				}
+				if f.Cmp(expected) != 0 || f.Signbit() != expected.Signbit() || f.Acc() != expected.Acc() || f.Prec() != prec || f.Mode() != mode {
+					t.Fatalf("%s %d %s: expected %s (%s), got %s (%s)", s, prec, mode,
+						expected.Text('p', 0), expected.Acc(), f.Text('p', 0), f.Acc())
+				}
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		s    string
+		prec uint
+	}{
+		{"1", 64},
+		{"123456789012345678901234567890", 97},
+		{"1.5", 64},
+		{"1E-30", 100},
+	} {
+		f, err := newDecimal(t, testCtx, tc.s).BigFloat(0, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Prec() != tc.prec {
+			t.Errorf("%s: expected precision %d, got %d", tc.s, tc.prec, f.Prec())
+		}
+	}
+
+	f, err := newDecimal(t, testCtx, "-Infinity").BigFloat(53, big.ToNearestEven)
+	if err != nil || !f.IsInf() || f.Sign() > 0 {
+		t.Fatalf("expected -Inf, got %v, %v", f, err)
+	}
+	if _, err := newDecimal(t, testCtx, "NaN").BigFloat(53, big.ToNearestEven); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Beyond the exponent range of big.Float, values overflow and underflow
+	// as in big.Float arithmetic.
+	huge := new(big.Float).SetMantExp(big.NewFloat(1), big.MaxExp-1)
+	tiny := new(big.Float).SetMantExp(big.NewFloat(1), big.MinExp)
+	for _, tc := range []struct {
+		s        string
+		expected *big.Float
+	}{
+		{"1E+646456994", new(big.Float).Mul(huge, big.NewFloat(2))},
+		{"-9.9E+999999999", new(big.Float).Mul(huge, big.NewFloat(-2))},
+		{"1E-646456996", new(big.Float).Quo(tiny, big.NewFloat(4))},
+		{"-1E-999999999", new(big.Float).Quo(tiny, big.NewFloat(-4))},
+	} {
+		f, err := newDecimal(t, testCtx, tc.s).BigFloat(53, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Cmp(tc.expected) != 0 || f.Signbit() != tc.expected.Signbit() || f.Acc() != tc.expected.Acc() {
+			t.Errorf("%s: expected %s (%s), got %s (%s)", tc.s,
+				tc.expected.Text('p', 0), tc.expected.Acc(), f.Text('p', 0), f.Acc())
+		}
+	}
+}
+
+// TestBigFloatLargeExponents checks that converting values whose powers of ten
+// have millions of digits does work bounded by the precision, and that the
+// results agree with conversions at a higher precision.
+func TestBigFloatLargeExponents(t *testing.T) {
+	for _, tc := range []struct {
+		s   string
+		exp int
+	}{
+		{"1E+10000000", 33219281},
+		{"-7.25E+9999999", 33219281},
+		{"1E-10000000", -33219280},
+		{"3E-10000001", -33219282},
+		{"1E+646456992", 2147483644},
+		{"1E-646456992", -2147483643},
+		{"9.9E+646456993", 0},
+		{"-1E-646456994", 0},
+	} {
+		d := newDecimal(t, testCtx, tc.s)
+		wide, err := d.BigFloat(200, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// An exp of 0 marks values that overflow or underflow.
+		if tc.exp == 0 && !wide.IsInf() && wide.Sign() != 0 || tc.exp != 0 && wide.MantExp(nil) != tc.exp {
+			t.Fatalf("%s: unexpected result %s", tc.s, wide.Text('p', 0))
+		}
+		for _, mode := range bigFloatModes {
+			f, err := d.BigFloat(53, mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := new(big.Float).SetPrec(53).SetMode(mode).Set(wide)
+			if f.Cmp(expected) != 0 || f.Acc() == big.Exact {
+				t.Fatalf("%s %s: expected %s, got %s (%s)", tc.s, mode, expected.Text('p', 0), f.Text('p', 0), f.Acc())
+			}
+		}
+	}
+}
+
+// TestBigFloatRoundTrip checks that converting a big.Float to a Decimal and
+// back at the same precision is exact.
+func TestBigFloatRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		prec := uint(rnd.Intn(200) + 1)
+		x := new(big.Float).SetPrec(prec).SetFloat64(rnd.NormFloat64())
+		x.SetMantExp(x, rnd.Intn(600)-300)
+		var d Decimal
+		if _, err := d.SetBigFloat(x); err != nil {
+			t.Fatal(err)
+		}
+		f, err := d.BigFloat(prec, big.ToNearestEven)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Cmp(x) != 0 || f.Acc() != big.Exact {
+			t.Fatalf("%s: got %s (%s)", x.Text('p', 0), f.Text('p', 0), f.Acc())
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The binary encoding of a Decimal is:
+//
+//	version byte
+//	header byte: bits 0-1 Form, bit 2 Negative, bit 3 small coefficient
+//	exponent as a zig-zag varint (finite only)
+//	coefficient (finite only):
+//	  small: the coefficient as a uvarint
+//	  large: the byte length as a uvarint, followed by the big-endian bytes
+//
+// Coefficients that fit in a uint64 use the small encoding. Infinities and
+// NaNs have no exponent or coefficient.
+const (
+	binaryVersion = 1
+
+	binaryFormMask    = 0x03
+	binaryNegative    = 0x04
+	binarySmallCoeff  = 0x08
+	binaryHeaderValid = binaryFormMask | binaryNegative | binarySmallCoeff
+)
+
+var errBinaryShort = errors.New("binary encoding too short")
+
+// AppendBinary appends the binary encoding of d to buf and returns the
+// extended buffer.
+func (d *Decimal) AppendBinary(buf []byte) ([]byte, error) {
+	header := byte(d.Form) & binaryFormMask
+	if d.Negative {
+		header |= binaryNegative
+	}
+	if d.Form != Finite {
+		return append(buf, binaryVersion, header), nil
+	}
+	small := d.Coeff.IsUint64()
+	if small {
+		header |= binarySmallCoeff
+	}
+	var scratch [binary.MaxVarintLen64]byte
+	buf = append(buf, binaryVersion, header)
+	buf = append(buf, scratch[:binary.PutVarint(scratch[:], int64(d.Exponent))]...)
+	if small {
+		return append(buf, scratch[:binary.PutUvarint(scratch[:], d.Coeff.Uint64())]...), nil
+	}
+	n := (d.Coeff.BitLen() + 7) / 8
+	buf = append(buf, scratch[:binary.PutUvarint(scratch[:], uint64(n))]...)
+	start := len(buf)
+	for i := 0; i < n; i++ {
+		buf = append(buf, 0)
+	}
+	d.Coeff.FillBytes(buf[start:])
+	return buf, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (d *Decimal) MarshalBinary() ([]byte, error) {
+	return d.AppendBinary(nil)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (d *Decimal) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return errBinaryShort
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("unsupported binary encoding version %d", data[0])
+	}
+	header := data[1]
+	if header&^binaryHeaderValid != 0 {
+		return fmt.Errorf("invalid binary encoding header %#x", header)
+	}
+	form := Form(header & binaryFormMask)
+	neg := header&binaryNegative != 0
+	data = data[2:]
+	if form != Finite {
+		if len(data) != 0 || header&binarySmallCoeff != 0 {
+			return fmt.Errorf("invalid binary encoding of %s", form)
+		}
+		d.Form = form
+		d.Negative = neg
+		d.Exponent = 0
+		d.Coeff.SetInt64(0)
+		return nil
+	}
+	exp, n := binary.Varint(data)
+	if n <= 0 {
+		return errBinaryShort
+	}
+	if exp < -1<<31 || exp > 1<<31-1 {
+		return fmt.Errorf("binary encoding exponent %d out of range", exp)
+	}
+	data = data[n:]
+	u, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errBinaryShort
+	}
+	data = data[n:]
+	if header&binarySmallCoeff != 0 {
+		if len(data) != 0 {
+			return errors.New("trailing bytes in binary encoding")
+		}
+		d.Coeff.SetUint64(u)
+	} else {
+		if uint64(len(data)) != u {
+			return errors.New("invalid coefficient length in binary encoding")
+		}
+		d.Coeff.SetBytes(data)
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = int32(exp)
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface. It uses the same
+// encoding as MarshalBinary.
+func (d *Decimal) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (d *Decimal) GobDecode(buf []byte) error {
+	return d.UnmarshalBinary(buf)
+}
+
+// AppendText appends the textual form of d, as returned by MarshalText, to buf
+// and returns the extended buffer.
+func (d *Decimal) AppendText(buf []byte) ([]byte, error) {
+	if d == nil {
+		return append(buf, "<nil>"...), nil
+	}
+	return d.Append(buf, 'G'), nil
+}
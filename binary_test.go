@@ -0,0 +1,178 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"testing"
+)
+
+func TestBinaryEncoding(t *testing.T) {
+	tests := []struct {
+		s   string
+		hex string
+	}{
+		{s: "0", hex: "01080000"},
+		{s: "-0", hex: "010c0000"},
+		{s: "1", hex: "01080001"},
+		{s: "-1.5", hex: "010c010f"},
+		{s: "1.2E+3", hex: "0108040c"},
+		{s: "18446744073709551615", hex: "010800ffffffffffffffffff01"},
+		{s: "18446744073709551616", hex: "0100000901" + "0000000000000000"},
+		{s: "-1234567890123456789012345678901234567890E-1000", hex: ""},
+		{s: "NaN", hex: "0103"},
+		{s: "-NaN", hex: "0107"},
+		{s: "sNaN", hex: "0102"},
+		{s: "Inf", hex: "0101"},
+		{s: "-Inf", hex: "0105"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			testBinaryRoundTrip(t, newDecimal(t, testCtx, tc.s), tc.hex)
+		})
+	}
+	// Exponents outside of the range accepted by SetString.
+	testBinaryRoundTrip(t, &Decimal{Exponent: 1<<31 - 1, Coeff: *NewBigInt(1)}, "0108feffffff0f01")
+	testBinaryRoundTrip(t, &Decimal{Exponent: -1 << 31, Coeff: *NewBigInt(1)}, "0108ffffffff0f01")
+}
+
+func testBinaryRoundTrip(t *testing.T, d *Decimal, expected string) {
+	t.Helper()
+	b, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected != "" {
+		if h := hex.EncodeToString(b); h != expected {
+			t.Fatalf("%s: expected %s, got %s", d, expected, h)
+		}
+	}
+	// Decode into a Decimal with a large coefficient to verify all fields are
+	// overwritten.
+	var r Decimal
+	r.Coeff.SetString("98765432109876543210987654321", 10)
+	r.Negative = true
+	r.Exponent = 5
+	if err := r.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if r.Form != d.Form || r.Negative != d.Negative || (d.Form == Finite && r.CmpTotal(d) != 0) {
+		t.Fatalf("expected %s, got %s", d, &r)
+	}
+	if r.Form == Finite && r.Exponent != d.Exponent {
+		t.Fatalf("expected exponent %d, got %d", d.Exponent, r.Exponent)
+	}
+
+	// AppendBinary must not modify the existing contents of buf.
+	b2, err := d.AppendBinary([]byte("prefix"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b2, append([]byte("prefix"), b...)) {
+		t.Fatalf("unexpected AppendBinary result %x", b2)
+	}
+}
+
+func TestBinaryEncodingErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"01",
+		"0208",
+		"0110",
+		"010900",
+		"0108",
+		"010800",
+		"0108000102",
+		"01000002ff",
+		"01000001ffff",
+		"0108ffffffff1f00",
+	}
+	for _, h := range tests {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Decimal
+		if err := d.UnmarshalBinary(b); err == nil {
+			t.Errorf("%s: expected error, got %s", h, &d)
+		}
+	}
+}
+
+func TestGobEncoding(t *testing.T) {
+	type row struct {
+		A Decimal
+		B *Decimal
+		C []Decimal
+	}
+	in := row{
+		A: *newDecimal(t, testCtx, "-12.345"),
+		B: newDecimal(t, testCtx, "1234567890123456789012345678901234567890E-20"),
+		C: []Decimal{
+			*newDecimal(t, testCtx, "NaN"),
+			*newDecimal(t, testCtx, "-Inf"),
+			*newDecimal(t, testCtx, "0E+3"),
+		},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&in); err != nil {
+		t.Fatal(err)
+	}
+	var out row
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.A.CmpTotal(&in.A) != 0 || out.B.CmpTotal(in.B) != 0 || len(out.C) != len(in.C) {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+	for i := range in.C {
+		if out.C[i].String() != in.C[i].String() {
+			t.Fatalf("expected %s, got %s", &in.C[i], &out.C[i])
+		}
+	}
+}
+
+func TestAppendText(t *testing.T) {
+	for _, s := range []string{"0", "-1.50", "1.2E+3", "NaN", "-Infinity"} {
+		d := newDecimal(t, testCtx, s)
+		b, err := d.AppendText([]byte("x="))
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, _ := d.MarshalText()
+		if string(b) != "x="+string(m) {
+			t.Fatalf("expected x=%s, got %s", m, b)
+		}
+	}
+	var d *Decimal
+	if b, _ := d.AppendText(nil); string(b) != "<nil>" {
+		t.Fatalf("expected <nil>, got %s", b)
+	}
+}
+
+func TestAppendBinaryAllocs(t *testing.T) {
+	d := New(-123456789, -4)
+	var buf [32]byte
+	var r Decimal
+	allocs := testing.AllocsPerRun(100, func() {
+		b, _ := d.AppendBinary(buf[:0])
+		_ = r.UnmarshalBinary(b)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// CBOR (RFC 8949) represents decimals with the decimal fraction tag 4, whose
+// content is the array [exponent, mantissa]. The exponent is an integer, and
+// the mantissa is an integer or, if it doesn't fit in 64 bits, a bignum (tags 2
+// and 3, whose content is the big-endian bytes of n for the value n or -1-n).
+const (
+	cborMajorUint     = 0
+	cborMajorNegInt   = 1
+	cborMajorBytes    = 2
+	cborMajorArray    = 4
+	cborMajorTag      = 6
+	cborTagPosBignum  = 2
+	cborTagNegBignum  = 3
+	cborTagDecimal    = 4
+	cborFloat16       = 0xf9
+	cborFloat32       = 0xfa
+	cborFloat64       = 0xfb
+	cborHalfNaN       = 0x7e00
+	cborHalfInf       = 0x7c00
+	cborHalfNegInf    = 0xfc00
+	cborAdditionalMax = 27
+)
+
+var errCBORShort = errors.New("cbor: unexpected end of data")
+
+// AppendCBOR appends the CBOR encoding of d to buf and returns the extended
+// buffer. Finite values with a zero exponent are encoded as integers, and
+// others as decimal fractions. NaNs and infinities are encoded as
+// half-precision floats. CBOR integers have no negative zero, so the sign of
+// zero is not preserved.
+func (d *Decimal) AppendCBOR(buf []byte) ([]byte, error) {
+	switch d.Form {
+	case NaN, NaNSignaling:
+		return append(buf, cborFloat16, cborHalfNaN>>8, cborHalfNaN&0xff), nil
+	case Infinite:
+		if d.Negative {
+			return append(buf, cborFloat16, cborHalfNegInf>>8, cborHalfNegInf&0xff), nil
+		}
+		return append(buf, cborFloat16, cborHalfInf>>8, cborHalfInf&0xff), nil
+	}
+	if d.Exponent != 0 {
+		buf = appendCBORHead(buf, cborMajorTag, cborTagDecimal)
+		buf = appendCBORHead(buf, cborMajorArray, 2)
+		if d.Exponent < 0 {
+			buf = appendCBORHead(buf, cborMajorNegInt, uint64(-int64(d.Exponent)-1))
+		} else {
+			buf = appendCBORHead(buf, cborMajorUint, uint64(d.Exponent))
+		}
+	}
+	return appendCBORInteger(buf, &d.Coeff, d.Negative && d.Coeff.Sign() != 0), nil
+}
+
+// MarshalCBOR returns the CBOR encoding of d, as described in AppendCBOR.
+func (d *Decimal) MarshalCBOR() ([]byte, error) {
+	return d.AppendCBOR(nil)
+}
+
+// appendCBORHead appends the initial byte and argument of a data item.
+func appendCBORHead(buf []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(buf, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return append(buf, major|25, byte(arg>>8), byte(arg))
+	case arg <= math.MaxUint32:
+		return append(buf, major|26, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	}
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], arg)
+	return append(append(buf, major|27), tmp[:]...)
+}
+
+// appendCBORInteger appends the integer with magnitude m and the given sign,
+// as a bignum if necessary.
+func appendCBORInteger(buf []byte, m *BigInt, neg bool) []byte {
+	if !neg {
+		if m.IsUint64() {
+			return appendCBORHead(buf, cborMajorUint, m.Uint64())
+		}
+		buf = appendCBORHead(buf, cborMajorTag, cborTagPosBignum)
+		return appendCBORBytes(buf, m)
+	}
+	// Negative integers are encoded as -1-n.
+	var n BigInt
+	n.Sub(m, bigOne)
+	if n.IsUint64() {
+		return appendCBORHead(buf, cborMajorNegInt, n.Uint64())
+	}
+	buf = appendCBORHead(buf, cborMajorTag, cborTagNegBignum)
+	return appendCBORBytes(buf, &n)
+}
+
+// appendCBORBytes appends a byte string holding the big-endian bytes of m.
+func appendCBORBytes(buf []byte, m *BigInt) []byte {
+	n := (m.BitLen() + 7) / 8
+	buf = appendCBORHead(buf, cborMajorBytes, uint64(n))
+	start := len(buf)
+	for i := 0; i < n; i++ {
+		buf = append(buf, 0)
+	}
+	m.FillBytes(buf[start:])
+	return buf
+}
+
+// UnmarshalCBOR sets d to the value of data, which must contain a single CBOR
+// data item: a decimal fraction, an integer, a bignum, or a floating-point
+// number.
+func (d *Decimal) UnmarshalCBOR(data []byte) error {
+	rest, err := d.decodeCBOR(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("cbor: trailing data")
+	}
+	return nil
+}
+
+func (d *Decimal) decodeCBOR(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, errCBORShort
+	}
+	switch b[0] {
+	case cborFloat16:
+		if len(b) < 3 {
+			return nil, errCBORShort
+		}
+		return b[3:], d.setCBORFloat(float16ToFloat64(binary.BigEndian.Uint16(b[1:])))
+	case cborFloat32:
+		if len(b) < 5 {
+			return nil, errCBORShort
+		}
+		return b[5:], d.setCBORFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(b[1:]))))
+	case cborFloat64:
+		if len(b) < 9 {
+			return nil, errCBORShort
+		}
+		return b[9:], d.setCBORFloat(math.Float64frombits(binary.BigEndian.Uint64(b[1:])))
+	}
+	major, arg, rest, err := readCBORHead(b)
+	if err != nil {
+		return nil, err
+	}
+	exp := int64(0)
+	if major == cborMajorTag && arg == cborTagDecimal {
+		major, arg, rest, err = readCBORHead(rest)
+		if err != nil {
+			return nil, err
+		}
+		if major != cborMajorArray || arg != 2 {
+			return nil, errors.New("cbor: decimal fraction is not an array of two items")
+		}
+		major, arg, rest, err = readCBORHead(rest)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case major == cborMajorUint && arg <= math.MaxInt32:
+			exp = int64(arg)
+		case major == cborMajorNegInt && arg <= math.MaxInt32:
+			exp = -1 - int64(arg)
+		default:
+			return nil, errors.New("cbor: decimal fraction exponent is not an int32")
+		}
+		major, arg, rest, err = readCBORHead(rest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var coeff BigInt
+	var neg bool
+	switch {
+	case major == cborMajorUint:
+		coeff.SetUint64(arg)
+	case major == cborMajorNegInt:
+		coeff.SetUint64(arg)
+		coeff.Add(&coeff, bigOne)
+		neg = true
+	case major == cborMajorTag && (arg == cborTagPosBignum || arg == cborTagNegBignum):
+		neg = arg == cborTagNegBignum
+		major, arg, rest, err = readCBORHead(rest)
+		if err != nil {
+			return nil, err
+		}
+		if major != cborMajorBytes {
+			return nil, errors.New("cbor: bignum content is not a byte string")
+		}
+		if uint64(len(rest)) < arg {
+			return nil, errCBORShort
+		}
+		coeff.SetBytes(rest[:arg])
+		rest = rest[arg:]
+		if neg {
+			coeff.Add(&coeff, bigOne)
+		}
+	default:
+		return nil, fmt.Errorf("cbor: cannot decode major type %d into a Decimal", major)
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = int32(exp)
+	d.Coeff.Set(&coeff)
+	return rest, nil
+}
+
+// readCBORHead reads the initial byte and argument of a data item. Indefinite
+// lengths are not supported.
+func readCBORHead(b []byte) (major byte, arg uint64, rest []byte, err error) {
+	if len(b) == 0 {
+		return 0, 0, nil, errCBORShort
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+	b = b[1:]
+	if info < 24 {
+		return major, uint64(info), b, nil
+	}
+	if info > cborAdditionalMax {
+		return 0, 0, nil, fmt.Errorf("cbor: unsupported additional information %d", info)
+	}
+	n := 1 << (info - 24)
+	if len(b) < n {
+		return 0, 0, nil, errCBORShort
+	}
+	for _, c := range b[:n] {
+		arg = arg<<8 | uint64(c)
+	}
+	return major, arg, b[n:], nil
+}
+
+func (d *Decimal) setCBORFloat(f float64) error {
+	_, err := d.SetFloat64(f)
+	return err
+}
+
+// float16ToFloat64 returns the value of an IEEE 754 half-precision float.
+func float16ToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+	return sign * math.Ldexp(mant+1024, exp-25)
+}
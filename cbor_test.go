@@ -0,0 +1,216 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestCBOREncoding(t *testing.T) {
+	tests := []struct {
+		s   string
+		hex string
+		// decoded is the value read back, if it differs from s.
+		decoded string
+	}{
+		// RFC 8949 section 3.4.4.
+		{s: "273.15", hex: "c48221196ab3"},
+		// RFC 8949 appendix A.
+		{s: "0", hex: "00"},
+		{s: "1", hex: "01"},
+		{s: "10", hex: "0a"},
+		{s: "23", hex: "17"},
+		{s: "24", hex: "1818"},
+		{s: "100", hex: "1864"},
+		{s: "1000", hex: "1903e8"},
+		{s: "1000000", hex: "1a000f4240"},
+		{s: "1000000000000", hex: "1b000000e8d4a51000"},
+		{s: "18446744073709551615", hex: "1bffffffffffffffff"},
+		{s: "18446744073709551616", hex: "c249010000000000000000"},
+		{s: "-18446744073709551616", hex: "3bffffffffffffffff"},
+		{s: "-18446744073709551617", hex: "c349010000000000000000"},
+		{s: "-1", hex: "20"},
+		{s: "-10", hex: "29"},
+		{s: "-100", hex: "3863"},
+		{s: "-1000", hex: "3903e7"},
+		{s: "NaN", hex: "f97e00"},
+		{s: "Infinity", hex: "f97c00"},
+		{s: "-Infinity", hex: "f9fc00"},
+
+		{s: "-0", hex: "00", decoded: "0"},
+		{s: "sNaN", hex: "f97e00", decoded: "NaN"},
+		{s: "1.5", hex: "c482200f"},
+		{s: "-1.5", hex: "c482202e"},
+		{s: "1.2E+3", hex: "c482020c"},
+		{s: "0.00", hex: "c4822100"},
+		{s: "1E+24", hex: "c482181801"},
+		{s: "-123456789012345678901234567890E-20", hex: "c48233c34d018ee90ff6c373e0ee4e3f0ad1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d := newDecimal(t, testCtx, tc.s)
+			b, err := d.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h := hex.EncodeToString(b); h != tc.hex {
+				t.Fatalf("expected %s, got %s", tc.hex, h)
+			}
+			expected := tc.s
+			if tc.decoded != "" {
+				expected = tc.decoded
+			}
+			var r Decimal
+			r.Coeff.SetString("98765432109876543210987654321", 10)
+			r.Exponent = 7
+			if err := r.UnmarshalCBOR(b); err != nil {
+				t.Fatal(err)
+			}
+			if s := r.String(); s != newDecimal(t, testCtx, expected).String() {
+				t.Fatalf("expected %s, got %s", expected, s)
+			}
+
+			// AppendCBOR must not modify the existing contents of buf.
+			buf, err := d.AppendCBOR([]byte("prefix"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(buf) != "prefix"+string(b) {
+				t.Fatalf("unexpected append result %x", buf)
+			}
+		})
+	}
+
+	// Exponents outside of the range accepted by SetString.
+	for _, tc := range []struct {
+		d   Decimal
+		hex string
+	}{
+		{d: Decimal{Exponent: 1<<31 - 1, Coeff: *NewBigInt(1)}, hex: "c4821a7fffffff01"},
+		{d: Decimal{Exponent: -1 << 31, Coeff: *NewBigInt(1)}, hex: "c4823a7fffffff01"},
+	} {
+		b, err := tc.d.MarshalCBOR()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h := hex.EncodeToString(b); h != tc.hex {
+			t.Fatalf("expected %s, got %s", tc.hex, h)
+		}
+		var r Decimal
+		if err := r.UnmarshalCBOR(b); err != nil {
+			t.Fatal(err)
+		}
+		if r.Exponent != tc.d.Exponent || r.Coeff.Cmp(&tc.d.Coeff) != 0 {
+			t.Fatalf("expected %s, got %s", &tc.d, &r)
+		}
+	}
+}
+
+func TestCBORDecodeFloats(t *testing.T) {
+	// RFC 8949 appendix A.
+	tests := []struct {
+		hex string
+		s   string
+	}{
+		{hex: "f90000", s: "0"},
+		{hex: "f98000", s: "-0"},
+		{hex: "f93c00", s: "1"},
+		{hex: "fb3ff199999999999a", s: "1.1"},
+		{hex: "f93e00", s: "1.5"},
+		{hex: "f97bff", s: "65504"},
+		{hex: "fa47c35000", s: "100000"},
+		{hex: "fa7f7fffff", s: "3.4028234663852886E+38"},
+		{hex: "fb7e37e43c8800759c", s: "1E+300"},
+		{hex: "f90001", s: "5.960464477539063E-8"},
+		{hex: "f90400", s: "0.00006103515625"},
+		{hex: "f9c400", s: "-4"},
+		{hex: "fbc010666666666666", s: "-4.1"},
+		{hex: "f97c00", s: "Infinity"},
+		{hex: "f97e00", s: "NaN"},
+		{hex: "f9fc00", s: "-Infinity"},
+		{hex: "fa7f800000", s: "Infinity"},
+		{hex: "fa7fc00000", s: "NaN"},
+		{hex: "faff800000", s: "-Infinity"},
+		{hex: "fb7ff0000000000000", s: "Infinity"},
+		{hex: "fb7ff8000000000000", s: "NaN"},
+		{hex: "fbfff0000000000000", s: "-Infinity"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.hex, func(t *testing.T) {
+			b, err := hex.DecodeString(tc.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var d Decimal
+			if err := d.UnmarshalCBOR(b); err != nil {
+				t.Fatal(err)
+			}
+			expected := newDecimal(t, testCtx, tc.s)
+			if d.Form != expected.Form || d.Negative != expected.Negative ||
+				(d.Form == Finite && d.Cmp(expected) != 0) {
+				t.Fatalf("expected %s, got %s", expected, &d)
+			}
+		})
+	}
+}
+
+func TestCBORDecodeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		// Truncated arguments and floats.
+		"19",
+		"1903",
+		"f97e",
+		"fa7fc000",
+		"fb7ff8",
+		// Truncated bignum.
+		"c249010000",
+		// Trailing data.
+		"0000",
+		// Indefinite lengths and reserved additional information.
+		"1f",
+		"1c",
+		"c25f4101ff",
+		// Other major types: text string, array, map, simple values.
+		"6161",
+		"80",
+		"a0",
+		"f4",
+		"f6",
+		// Bigfloat (tag 5).
+		"c5822003",
+		// Decimal fractions that are not an array of two integers.
+		"c48121",
+		"c4832101ff",
+		"c4a0",
+		"c482c2410101",
+		"c4821a8000000001",
+		"c4823a8000000001",
+		"c4822161",
+		// Bignum content that is not a byte string.
+		"c201",
+	}
+	for _, tc := range tests {
+		b, err := hex.DecodeString(tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Decimal
+		if err := d.UnmarshalCBOR(b); err == nil {
+			t.Errorf("%s: expected error, got %s", tc, &d)
+		}
+	}
+}
@@ -18,11 +18,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 )
 
 // Context maintains options for Decimal operations. It can safely be used
-// concurrently, but not modified concurrently. Arguments for any method
-// can safely be used as both result and operand.
+// concurrently, but not modified concurrently. A Context with a Workspace
+// must not be used concurrently, and neither may the copies made by
+// WithPrecision, which share the same Workspace. Arguments for any method can
+// safely be used as both result and operand.
 type Context struct {
 	// Precision is the number of places to round during rounding; this is
 	// effectively the total number of digits (before and after the decimal
@@ -42,6 +45,10 @@ type Context struct {
 	// Rounding specifies the Rounder to use during rounding. RoundHalfUp is used if
 	// empty or not present in Roundings.
 	Rounding Rounder
+	// Workspace, if not nil, holds temporary values that are reused across
+	// operations. It is shared with the Contexts that functions such as Exp
+	// derive from this one.
+	Workspace *Workspace
 }
 
 const (
@@ -70,7 +77,8 @@ var BaseContext = Context{
 	Traps: DefaultTraps,
 }
 
-// WithPrecision returns a copy of c but with the specified precision.
+// WithPrecision returns a copy of c but with the specified precision. The
+// copy shares the Workspace of c.
 func (c *Context) WithPrecision(p uint32) *Context {
 	r := new(Context)
 	*r = *c
@@ -146,8 +154,22 @@ func (c *Context) add(d, x, y *Decimal, subtract bool) (Condition, error) {
 		}
 		return 0, nil
 	}
-	var tmp BigInt
-	a, b, s, err := upscale(x, y, &tmp)
+	if xs, ok := x.decimal128(); ok {
+		if ys, ok := y.decimal128(); ok {
+			if r, res, ok := c.addUint128(xs, ys, subtract); ok {
+				d.SetDecimal128(r)
+				return c.goError(res)
+			}
+		}
+	}
+	var sx, sy Decimal
+	x, y = c.shrinkAddends(x, y, &sx, &sy)
+	var ltmp BigInt
+	tmp := &ltmp
+	if w := c.Workspace; w != nil {
+		tmp = &w.add
+	}
+	a, b, s, err := upscale(x, y, tmp)
 	if err != nil {
 		return 0, fmt.Errorf("add: %w", err)
 	}
@@ -170,6 +192,55 @@ func (c *Context) add(d, x, y *Decimal, subtract bool) (Condition, error) {
 	return c.goError(res)
 }
 
+// shrinkAddends returns x and y, or stand-ins for them in sx and sy that are
+// closer in exponent to the other operand but have the same rounded sum in c.
+// This avoids aligning operands with a large power of ten. A zero with the
+// larger exponent is replaced by a zero with the exponent of the other
+// operand, which does not change the exact sum. If c.Precision is not 0, an
+// operand too small to affect the rounded sum other than by its sign is
+// replaced by a smaller power of ten, and a zero by one with a larger
+// exponent, since the sum is rounded anyway.
+func (c *Context) shrinkAddends(x, y, sx, sy *Decimal) (*Decimal, *Decimal) {
+	if x.Exponent == y.Exponent {
+		return x, y
+	}
+	swapped := x.Exponent < y.Exponent
+	if swapped {
+		x, y, sx, sy = y, x, sy, sx
+	}
+	// x has the larger exponent.
+	if x.IsZero() {
+		sx.setSpecial(Finite, x.Negative)
+		sx.Exponent = y.Exponent
+		x = sx
+	} else if c.Precision != 0 {
+		// The sum has an adjusted exponent of at least adj(x)-1, so it is
+		// rounded to a multiple of 10^(sticky+1), and x and the rounding
+		// boundaries are multiples of 10^sticky. An operand below 10^sticky
+		// in magnitude only decides which side of x the sum lies on.
+		sticky := int64(x.Exponent) + x.NumDigits() - int64(c.Precision) - 2
+		if e := int64(x.Exponent); e < sticky {
+			sticky = e
+		}
+		if y.IsZero() {
+			if int64(y.Exponent) < sticky {
+				sy.setSpecial(Finite, y.Negative)
+				sy.Exponent = int32(sticky)
+				y = sy
+			}
+		} else if int64(y.Exponent)+y.NumDigits() <= sticky {
+			sy.setSpecial(Finite, y.Negative)
+			sy.Coeff.SetInt64(1)
+			sy.Exponent = int32(sticky - 1)
+			y = sy
+		}
+	}
+	if swapped {
+		return y, x
+	}
+	return x, y
+}
+
 // Add sets d to the sum x+y.
 func (c *Context) Add(d, x, y *Decimal) (Condition, error) {
 	return c.add(d, x, y, false)
@@ -180,6 +251,270 @@ func (c *Context) Sub(d, x, y *Decimal) (Condition, error) {
 	return c.add(d, x, y, true)
 }
 
+// Sum sets d to the sum of xs. Unlike repeated calls to Add, the sum is
+// computed exactly and rounded only once, so the result does not depend on
+// the order of xs. The sum of no operands is 0.
+func (c *Context) Sum(d *Decimal, xs ...*Decimal) (Condition, error) {
+	return c.sum(d, xs, nil)
+}
+
+// DotProduct sets d to the sum of the products xs[i]*ys[i]. The products and
+// their sum are computed exactly and rounded only once. xs and ys must have
+// the same length.
+func (c *Context) DotProduct(d *Decimal, xs, ys []*Decimal) (Condition, error) {
+	if len(xs) != len(ys) {
+		return 0, fmt.Errorf("DotProduct: mismatched lengths %d and %d", len(xs), len(ys))
+	}
+	return c.sum(d, xs, ys)
+}
+
+// sum implements Sum and DotProduct. If ys is nil, the terms of the sum are
+// the elements of xs. Otherwise they are the products xs[i]*ys[i]. All terms
+// are aligned to the smallest exponent once and accumulated exactly before a
+// single rounding of the result, unless they are too far apart to align
+// exactly, in which case sumGroups rounds them as Add does.
+func (c *Context) sum(d *Decimal, xs, ys []*Decimal) (Condition, error) {
+	// Per the method contract of setAsNaN, the first NaNSignaling takes
+	// precedence over the first NaN.
+	var nan *Decimal
+	for i, x := range xs {
+		for _, v := range [2]*Decimal{x, termY(ys, i)} {
+			if v == nil {
+				continue
+			}
+			if v.Form == NaNSignaling {
+				return c.setAsNaN(d, v, nil)
+			}
+			if v.Form == NaN && nan == nil {
+				nan = v
+			}
+		}
+	}
+	if nan != nil {
+		return c.setAsNaN(d, nan, nil)
+	}
+
+	// Determine the smallest and largest exponents of the finite terms and
+	// handle infinities, which can only cancel each other out into a NaN.
+	var posInf, negInf, allNeg, allPos bool
+	minExp, maxExp := int64(math.MaxInt64), int64(math.MinInt64)
+	allNeg, allPos = true, true
+	for i, x := range xs {
+		y := termY(ys, i)
+		neg := x.Negative
+		exp := int64(x.Exponent)
+		if y != nil {
+			neg = neg != y.Negative
+			exp += int64(y.Exponent)
+		}
+		if xi, yi := x.Form == Infinite, y != nil && y.Form == Infinite; xi || yi {
+			if y != nil && (x.IsZero() || y.IsZero()) {
+				d.Set(decimalNaN)
+				return c.goError(InvalidOperation)
+			}
+			if neg {
+				negInf = true
+			} else {
+				posInf = true
+			}
+			continue
+		}
+		allNeg = allNeg && neg
+		allPos = allPos && !neg
+		if exp < minExp {
+			minExp = exp
+		}
+		if exp > maxExp {
+			maxExp = exp
+		}
+	}
+	if posInf && negInf {
+		d.Set(decimalNaN)
+		return c.goError(InvalidOperation)
+	} else if posInf || negInf {
+		d.Set(decimalInfinity)
+		d.Negative = negInf
+		return 0, nil
+	}
+	if len(xs) == 0 {
+		d.SetInt64(0)
+		return 0, nil
+	}
+	// The sum of zeros is only negative if all of them were negative, and
+	// an exact cancellation of terms with different signs is only negative
+	// when rounding toward -Inf.
+	zeroNeg := allNeg || !allPos && c.Rounding == RoundFloor
+	if c.Precision != 0 && maxExp-minExp > maxExactScale {
+		return c.sumGroups(d, xs, ys, zeroNeg)
+	}
+
+	var acc, term, tmpE BigInt
+	for i, x := range xs {
+		if err := addTerm(&acc, &term, &tmpE, x, termY(ys, i), minExp); err != nil {
+			return 0, err
+		}
+	}
+	return c.roundSum(d, &acc, minExp, zeroNeg, ys != nil)
+}
+
+// sumTerm is a term of a sum with its exponent and an upper bound of its
+// adjusted exponent.
+type sumTerm struct {
+	x, y     *Decimal
+	exp, adj int64
+}
+
+// sumGroups implements sum for terms whose exponents are too far apart to be
+// aligned exactly, which c.Precision allows to round as Add does. The terms
+// are split into groups at exponent gaps wide enough that a nonzero sum of a
+// group exceeds the sum of all smaller groups by more than the precision and
+// the rounding digits. The first group with a nonzero sum is computed exactly,
+// and the smaller groups are replaced by a stand-in with the sign of their
+// total, which is that of the next group with a nonzero sum.
+func (c *Context) sumGroups(d *Decimal, xs, ys []*Decimal, zeroNeg bool) (Condition, error) {
+	terms := make([]sumTerm, 0, len(xs))
+	for i, x := range xs {
+		t := sumTerm{x: x, y: termY(ys, i), exp: int64(x.Exponent)}
+		t.adj = t.exp + x.NumDigits() - 1
+		if t.y != nil {
+			t.exp += int64(t.y.Exponent)
+			t.adj += int64(t.y.Exponent) + t.y.NumDigits()
+		}
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool { return terms[i].exp > terms[j].exp })
+	// maxAdj[i] is the largest adjusted exponent of terms[i:].
+	maxAdj := make([]int64, len(terms)+1)
+	maxAdj[len(terms)] = math.MinInt64
+	for i := len(terms) - 1; i >= 0; i-- {
+		maxAdj[i] = maxAdj[i+1]
+		if terms[i].adj > maxAdj[i] {
+			maxAdj[i] = terms[i].adj
+		}
+	}
+	// The sum of fewer than 10^19 terms below 10^(adj+1) is below
+	// 10^(adj+20), so a gap of Precision+22 keeps it below the last digit
+	// that Add looks at when rounding the sum of a larger group.
+	gap := int64(c.Precision) + 22
+	start := 0
+	var term, tmpE BigInt
+	// nextGroup sets acc to the exact sum of the next group and returns the
+	// smallest exponent in it.
+	nextGroup := func(acc *BigInt) (int64, error) {
+		acc.SetInt64(0)
+		end := start
+		for end+1 < len(terms) && terms[end].exp-maxAdj[end+1] <= gap {
+			end++
+		}
+		exp := terms[end].exp
+		for _, t := range terms[start : end+1] {
+			if err := addTerm(acc, &term, &tmpE, t.x, t.y, exp); err != nil {
+				return 0, err
+			}
+		}
+		start = end + 1
+		return exp, nil
+	}
+
+	var acc BigInt
+	var exp int64
+	for start < len(terms) {
+		var err error
+		if exp, err = nextGroup(&acc); err != nil {
+			return 0, err
+		}
+		if acc.Sign() != 0 {
+			break
+		}
+	}
+	// Without smaller groups the sum is exact. A sum whose exponent is
+	// outside of the range of Decimal overflows or underflows, which the
+	// smaller groups cannot change.
+	if start == len(terms) || exp > math.MaxInt32 || exp-gap < math.MinInt32 {
+		return c.roundSum(d, &acc, exp, zeroNeg, ys != nil)
+	}
+	var rest Decimal
+	var racc BigInt
+	for start < len(terms) {
+		if _, err := nextGroup(&racc); err != nil {
+			return 0, err
+		}
+		if racc.Sign() != 0 {
+			rest.Coeff.SetInt64(1)
+			rest.Negative = racc.Sign() < 0
+			break
+		}
+	}
+	rest.Exponent = int32(exp - gap)
+	d.Form = Finite
+	d.Negative = acc.Sign() < 0
+	d.Coeff.Abs(&acc)
+	d.Exponent = int32(exp)
+	return c.add(d, d, &rest, false)
+}
+
+// addTerm adds the term x*y, or x if y is nil, aligned to the exponent minExp
+// to acc. term and tmp are scratch space.
+func addTerm(acc, term, tmp *BigInt, x, y *Decimal, minExp int64) error {
+	neg := x.Negative
+	exp := int64(x.Exponent)
+	if y != nil {
+		neg = neg != y.Negative
+		exp += int64(y.Exponent)
+		term.Mul(&x.Coeff, &y.Coeff)
+	} else {
+		term.Set(&x.Coeff)
+	}
+	if term.Sign() == 0 {
+		return nil
+	}
+	if s := exp - minExp; s > maxExactScale {
+		return fmt.Errorf("sum: %w", errors.New(errExponentOutOfRangeStr))
+	} else if s > 0 {
+		term.Mul(term, tableExp10(s, tmp))
+	}
+	if neg {
+		acc.Sub(acc, term)
+	} else {
+		acc.Add(acc, term)
+	}
+	return nil
+}
+
+// roundSum sets d to the exact sum acc × 10^exp rounded by c. A zero sum is
+// negative if zeroNeg is set. If products is set, exp may be outside of the
+// range of Decimal.
+func (c *Context) roundSum(d *Decimal, acc *BigInt, exp int64, zeroNeg, products bool) (Condition, error) {
+	d.Form = Finite
+	switch acc.Sign() {
+	case -1:
+		d.Negative = true
+		d.Coeff.Neg(acc)
+	case 0:
+		d.Negative = zeroNeg
+		d.Coeff.SetInt64(0)
+	default:
+		d.Negative = false
+		d.Coeff.Set(acc)
+	}
+	var res Condition
+	if products {
+		res = d.setExponent(c, unknownNumDigits, 0, exp)
+	} else {
+		d.Exponent = int32(exp)
+	}
+	res |= c.round(d, d)
+	return c.goError(res)
+}
+
+// termY returns ys[i], or nil if ys is nil.
+func termY(ys []*Decimal, i int) *Decimal {
+	if ys == nil {
+		return nil
+	}
+	return ys[i]
+}
+
 // Abs sets d to |x| (the absolute value of x).
 func (c *Context) Abs(d, x *Decimal) (Condition, error) {
 	if c.shouldSetAsNaN(x, nil) {
@@ -216,6 +551,14 @@ func (c *Context) Mul(d, x, y *Decimal) (Condition, error) {
 		d.Negative = neg
 		return 0, nil
 	}
+	if xs, ok := x.decimal128(); ok {
+		if ys, ok := y.decimal128(); ok {
+			if r, res, ok := c.mulUint128(xs, ys); ok {
+				d.SetDecimal128(r)
+				return c.goError(res)
+			}
+		}
+	}
 
 	d.Coeff.Mul(&x.Coeff, &y.Coeff)
 	d.Negative = neg
@@ -283,13 +626,21 @@ func (c *Context) Quo(d, x, y *Decimal) (Condition, error) {
 	if set, res, err := c.quoSpecials(d, x, y, true); set {
 		return res, err
 	}
+	if xs, ok := x.decimal128(); ok {
+		if ys, ok := y.decimal128(); ok {
+			if r, res, ok := c.quo128(xs, ys); ok {
+				d.SetDecimal128(r)
+				return c.goError(res)
+			}
+		}
+	}
 
 	// The sign of the result is the exclusive or of the signs of the operands.
 	neg := x.Negative != y.Negative
 
 	// Shift the resulting exponent by the difference between the dividend and
 	// the divisor's exponent after performing arithmetic on the coefficients.
-	shift := int64(x.Exponent - y.Exponent)
+	shift := int64(x.Exponent) - int64(y.Exponent)
 
 	var res Condition
 	if x.IsZero() {
@@ -299,7 +650,11 @@ func (c *Context) Quo(d, x, y *Decimal) (Condition, error) {
 		return c.goError(res)
 	}
 
-	var dividend, divisor BigInt
+	var ldividend, ldivisor, lrem BigInt
+	dividend, divisor, rem := &ldividend, &ldivisor, &lrem
+	if w := c.Workspace; w != nil {
+		dividend, divisor, rem = &w.quo.dividend, &w.quo.divisor, &w.quo.rem
+	}
 	dividend.Abs(&x.Coeff)
 	divisor.Abs(&y.Coeff)
 
@@ -307,21 +662,21 @@ func (c *Context) Quo(d, x, y *Decimal) (Condition, error) {
 	// dividend is greater than or equal to the coefficient of the divisor and
 	// is also less than ten times the coefficient of the divisor. While doing
 	// so, keep track of how far the two have been adjusted.
-	ndDividend := NumDigits(&dividend)
-	ndDivisor := NumDigits(&divisor)
+	ndDividend := c.numDigits(dividend)
+	ndDivisor := c.numDigits(divisor)
 	ndDiff := ndDividend - ndDivisor
 	var tmpE BigInt
 	if ndDiff < 0 {
 		// numDigits(dividend) < numDigits(divisor), multiply dividend by 10^diff.
-		dividend.Mul(&dividend, tableExp10(-ndDiff, &tmpE))
+		dividend.Mul(dividend, c.tableExp10(-ndDiff, &tmpE))
 	} else if ndDiff > 0 {
 		// numDigits(dividend) > numDigits(divisor), multiply divisor by 10^diff.
-		divisor.Mul(&divisor, tableExp10(ndDiff, &tmpE))
+		divisor.Mul(divisor, c.tableExp10(ndDiff, &tmpE))
 	}
 	adjCoeffs := -ndDiff
-	if dividend.Cmp(&divisor) < 0 {
+	if dividend.Cmp(divisor) < 0 {
 		// dividend < divisor, multiply dividend by 10.
-		dividend.Mul(&dividend, bigTen)
+		dividend.Mul(dividend, bigTen)
 		adjCoeffs++
 	}
 
@@ -331,18 +686,17 @@ func (c *Context) Quo(d, x, y *Decimal) (Condition, error) {
 	// equal to the divisor, so the result will always be greater than or equal
 	// to 1.
 	adjExp10 := int64(c.Precision - 1)
-	dividend.Mul(&dividend, tableExp10(adjExp10, &tmpE))
+	dividend.Mul(dividend, c.tableExp10(adjExp10, &tmpE))
 
 	// Perform the division.
-	var rem BigInt
-	d.Coeff.QuoRem(&dividend, &divisor, &rem)
+	d.Coeff.QuoRem(dividend, divisor, rem)
 	d.Form = Finite
 	d.Negative = neg
 
 	// If there was a remainder, it is taken into account for rounding. To do
 	// so, we determine whether the remainder was more or less than half of the
 	// divisor and round accordingly.
-	nd := NumDigits(&d.Coeff)
+	nd := c.numDigits(&d.Coeff)
 	if rem.Sign() != 0 {
 		// Use the adjusted exponent to determine if we are Subnormal.
 		// If so, don't round. This computation of adj and the check
@@ -350,14 +704,21 @@ func (c *Context) Quo(d, x, y *Decimal) (Condition, error) {
 		adj := shift + (-adjCoeffs) + (-adjExp10) + nd - 1
 		if adj >= int64(c.MinExponent) {
 			res |= Inexact | Rounded
-			rem.Mul(&rem, bigTwo)
-			half := rem.Cmp(&divisor)
+			rem.Mul(rem, bigTwo)
+			half := rem.Cmp(divisor)
 			if c.Rounding.ShouldAddOne(&d.Coeff, d.Negative, half) {
 				d.Coeff.Add(&d.Coeff, bigOne)
 				// The coefficient changed, so recompute num digits in
 				// setExponent.
 				nd = unknownNumDigits
 			}
+		} else {
+			// setExponent rounds a subnormal result to fewer digits than
+			// it has, so append a sticky digit for the remainder.
+			d.Coeff.Mul(&d.Coeff, bigTen)
+			d.Coeff.Add(&d.Coeff, bigOne)
+			adjExp10++
+			nd++
 		}
 	}
 
@@ -371,25 +732,37 @@ func (c *Context) QuoInteger(d, x, y *Decimal) (Condition, error) {
 	if set, res, err := c.quoSpecials(d, x, y, false); set {
 		return res, err
 	}
+	if xs, ok := x.decimal128(); ok {
+		if ys, ok := y.decimal128(); ok {
+			if r, ok := c.quoInteger128(xs, ys); ok {
+				d.SetDecimal128(r)
+				return 0, nil
+			}
+		}
+	}
 
 	// The sign of the result is the exclusive or of the signs of the operands.
 	neg := x.Negative != y.Negative
-	var res Condition
 
-	var tmp BigInt
-	a, b, _, err := upscale(x, y, &tmp)
-	if err != nil {
-		return 0, fmt.Errorf("QuoInteger: %w", err)
+	bound := c.quoIntegerBound(x, y)
+	if bound == 0 {
+		var tmp BigInt
+		a, b, _, err := upscale(x, y, &tmp)
+		if err != nil {
+			return 0, fmt.Errorf("QuoInteger: %w", err)
+		}
+		d.Coeff.Quo(a, b)
+	} else {
+		d.Coeff.SetInt64(0)
 	}
-	d.Coeff.Quo(a, b)
 	d.Form = Finite
-	if d.NumDigits() > int64(c.Precision) {
+	if bound > 0 || d.NumDigits() > int64(c.Precision) {
 		d.Set(decimalNaN)
-		res |= DivisionImpossible
+		return c.goError(DivisionImpossible)
 	}
 	d.Exponent = 0
 	d.Negative = neg
-	return c.goError(res)
+	return 0, nil
 }
 
 // Rem sets d to the remainder part of the quotient x/y. If
@@ -418,25 +791,77 @@ func (c *Context) Rem(d, x, y *Decimal) (Condition, error) {
 		d.Set(decimalNaN)
 		return c.goError(res)
 	}
-	var tmp1 BigInt
-	a, b, s, err := upscale(x, y, &tmp1)
-	if err != nil {
-		return 0, fmt.Errorf("Rem: %w", err)
+	if xs, ok := x.decimal128(); ok {
+		if ys, ok := y.decimal128(); ok {
+			if r, res, ok := c.rem128(xs, ys); ok {
+				d.SetDecimal128(r)
+				return c.goError(res)
+			}
+		}
 	}
-	var tmp2 BigInt
-	tmp2.QuoRem(a, b, &d.Coeff)
-	if NumDigits(&tmp2) > int64(c.Precision) {
+	switch c.quoIntegerBound(x, y) {
+	case 1:
 		d.Set(decimalNaN)
 		return c.goError(DivisionImpossible)
+	case -1:
+		// The integer part is 0, which has more than 0 digits.
+		if c.Precision == 0 {
+			d.Set(decimalNaN)
+			return c.goError(DivisionImpossible)
+		}
+		// The remainder is x with the smaller exponent of x and y. Since
+		// |x| < |y|, a smaller exponent of y is within the digits of y.
+		exp := x.Exponent
+		if y.Exponent < exp {
+			exp = y.Exponent
+		}
+		d.Coeff.Set(&x.Coeff)
+		if exp < x.Exponent && d.Coeff.Sign() != 0 {
+			var tmpE BigInt
+			d.Coeff.Mul(&d.Coeff, tableExp10(int64(x.Exponent)-int64(exp), &tmpE))
+		}
+		d.Exponent = exp
+	default:
+		var tmp1 BigInt
+		a, b, s, err := upscale(x, y, &tmp1)
+		if err != nil {
+			return 0, fmt.Errorf("Rem: %w", err)
+		}
+		var tmp2 BigInt
+		tmp2.QuoRem(a, b, &d.Coeff)
+		if NumDigits(&tmp2) > int64(c.Precision) {
+			d.Set(decimalNaN)
+			return c.goError(DivisionImpossible)
+		}
+		d.Exponent = s
 	}
 	d.Form = Finite
-	d.Exponent = s
 	// The sign of the result is sign if the dividend.
 	d.Negative = x.Negative
 	res |= c.round(d, d)
 	return c.goError(res)
 }
 
+// quoIntegerBound bounds the integer part of x/y, for finite x and finite,
+// nonzero y, by the adjusted exponents of x and y. It returns -1 if the
+// integer part is 0, 1 if it has more than c.Precision digits, and 0 if x and
+// y must be aligned to tell, which then takes a power of ten no larger than
+// c.Precision and their digits allow.
+func (c *Context) quoIntegerBound(x, y *Decimal) int {
+	if x.IsZero() {
+		return -1
+	}
+	// 10^(adj(x)-adj(y)-1) < |x/y| < 10^(adj(x)-adj(y)+1).
+	diff := int64(x.Exponent) + x.NumDigits() - int64(y.Exponent) - y.NumDigits()
+	switch {
+	case diff < 0:
+		return -1
+	case diff > int64(c.Precision):
+		return 1
+	}
+	return 0
+}
+
 func (c *Context) rootSpecials(d, x *Decimal, factor int32) (bool, Condition, error) {
 	if c.shouldSetAsNaN(x, nil) {
 		res, err := c.setAsNaN(d, x, nil)
@@ -489,33 +914,37 @@ func (c *Context) Sqrt(d, x *Decimal) (Condition, error) {
 		workp = 7
 	}
 
-	var f Decimal
+	var local sqrtWorkspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.sqrt
+	}
+	f := &w.f
 	f.Set(x)
 	nd := x.NumDigits()
 	e := nd + int64(x.Exponent)
 	f.Exponent = int32(-nd)
-	nc := c.WithPrecision(workp)
+	nc := c.derivedContext(workspaceSqrt, c, workp)
 	nc.Rounding = RoundHalfEven
 	ed := MakeErrDecimal(nc)
 	// Set approx to the first guess, based on whether e (the exponent part of x)
 	// is odd or even.
-	var approx Decimal
+	approx, tmp := &w.approx, &w.tmp
 	if e%2 == 0 {
 		approx.SetFinite(819, -3)
-		ed.Mul(&approx, &approx, &f)
-		ed.Add(&approx, &approx, New(259, -3))
+		ed.Mul(approx, approx, f)
+		ed.Add(approx, approx, tmp.SetFinite(259, -3))
 	} else {
 		f.Exponent--
 		e++
 		approx.SetFinite(259, -2)
-		ed.Mul(&approx, &approx, &f)
-		ed.Add(&approx, &approx, New(819, -4))
+		ed.Mul(approx, approx, f)
+		ed.Add(approx, approx, tmp.SetFinite(819, -4))
 	}
 
 	// Now we repeatedly improve approx. Our precision improves quadratically,
 	// which we keep track of in p.
 	p := uint32(3)
-	var tmp Decimal
 
 	// The algorithm in the paper says to use c.Precision + 2. decNumber uses
 	// workp + 2. But we use workp + 5 to make the tests pass. This means it is
@@ -527,11 +956,11 @@ func (c *Context) Sqrt(d, x *Decimal) (Condition, error) {
 		}
 		nc.Precision = p
 		// tmp = f / approx
-		ed.Quo(&tmp, &f, &approx)
+		ed.Quo(tmp, f, approx)
 		// tmp = approx + f / approx
-		ed.Add(&tmp, &tmp, &approx)
+		ed.Add(tmp, tmp, approx)
 		// approx = 0.5 * (approx + f / approx)
-		ed.Mul(&approx, &tmp, decimalHalf)
+		ed.Mul(approx, tmp, decimalHalf)
 	}
 
 	// At this point the paper says: "approx is now within 1 ulp of the properly
@@ -547,7 +976,7 @@ func (c *Context) Sqrt(d, x *Decimal) (Condition, error) {
 		return 0, err
 	}
 
-	d.Set(&approx)
+	d.Set(approx)
 	d.Exponent += int32(e / 2)
 	nc.Precision = c.Precision
 	nc.Rounding = RoundHalfEven
@@ -566,11 +995,16 @@ func (c *Context) Cbrt(d, x *Decimal) (Condition, error) {
 		return res, err
 	}
 
-	var ax, z Decimal
+	var local cbrtWorkspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.cbrt
+	}
+	ax, z, z0 := &w.ax, &w.z, &w.z0
 	ax.Abs(x)
-	z.Set(&ax)
+	z.Set(ax)
 	neg := x.Negative
-	nc := BaseContext.WithPrecision(c.Precision*2 + 2)
+	nc := c.derivedContext(workspaceCbrt, &BaseContext, c.Precision*2+2)
 	ed := MakeErrDecimal(nc)
 	exp8 := 0
 
@@ -579,51 +1013,59 @@ func (c *Context) Cbrt(d, x *Decimal) (Condition, error) {
 	// https://people.freebsd.org/~lstewart/references/apple_tr_kt32_cuberoot.pdf
 	//
 	// Computing the cube root of any number is reduced to computing
-	// the cube root of a number between 0.125 and 1. After the next loops,
-	// x = z * 8^exp8 will hold.
+	// the cube root of a number between 0.125 and 1. First divide z by a
+	// power of 1000 so that it is between 0.001 and 1, which is exact and
+	// takes a single step regardless of the exponent. After the next loops,
+	// x = z * 8^exp8 * 1000^exp1000 will hold.
+	adj := int64(z.Exponent) + z.NumDigits() - 1
+	exp1000 := (adj + 3) / 3
+	if adj+3 < 0 && (adj+3)%3 != 0 {
+		exp1000--
+	}
+	z.Exponent = int32(int64(z.Exponent) - 3*exp1000)
 	for z.Cmp(decimalOneEighth) < 0 {
 		exp8--
-		ed.Mul(&z, &z, decimalEight)
+		ed.Mul(z, z, decimalEight)
 	}
 
 	for z.Cmp(decimalOne) > 0 {
 		exp8++
-		ed.Mul(&z, &z, decimalOneEighth)
+		ed.Mul(z, z, decimalOneEighth)
 	}
 
 	// Use this polynomial to approximate the cube root between 0.125 and 1.
 	// z = (-0.46946116 * z + 1.072302) * z + 0.3812513
 	// It will serve as an initial estimate, hence the precision of this
 	// computation may only impact performance, not correctness.
-	var z0 Decimal
-	z0.Set(&z)
-	ed.Mul(&z, &z, decimalCbrtC1)
-	ed.Add(&z, &z, decimalCbrtC2)
-	ed.Mul(&z, &z, &z0)
-	ed.Add(&z, &z, decimalCbrtC3)
+	z0.Set(z)
+	ed.Mul(z, z, decimalCbrtC1)
+	ed.Add(z, z, decimalCbrtC2)
+	ed.Mul(z, z, z0)
+	ed.Add(z, z, decimalCbrtC3)
 
 	for ; exp8 < 0; exp8++ {
-		ed.Mul(&z, &z, decimalHalf)
+		ed.Mul(z, z, decimalHalf)
 	}
 
 	for ; exp8 > 0; exp8-- {
-		ed.Mul(&z, &z, decimalTwo)
+		ed.Mul(z, z, decimalTwo)
 	}
+	z.Exponent = int32(int64(z.Exponent) + exp1000)
 
 	// Loop until convergence.
-	for loop := nc.newLoop("cbrt", &z, c.Precision+1, 1); ; {
+	for loop := nc.initLoop(&w.loop, "cbrt", z, c.Precision+1, 1); ; {
 		// z = (2.0 * z0 +  x / (z0 * z0) ) / 3.0;
-		z0.Set(&z)
-		ed.Mul(&z, &z, &z0)
-		ed.Quo(&z, &ax, &z)
-		ed.Add(&z, &z, &z0)
-		ed.Add(&z, &z, &z0)
-		ed.Quo(&z, &z, decimalThree)
+		z0.Set(z)
+		ed.Mul(z, z, z0)
+		ed.Quo(z, ax, z)
+		ed.Add(z, z, z0)
+		ed.Add(z, z, z0)
+		ed.Quo(z, z, decimalThree)
 
 		if err := ed.Err(); err != nil {
 			return 0, err
 		}
-		if done, err := loop.done(&z); err != nil {
+		if done, err := loop.done(z); err != nil {
 			return 0, err
 		} else if done {
 			break
@@ -631,20 +1073,20 @@ func (c *Context) Cbrt(d, x *Decimal) (Condition, error) {
 	}
 
 	z0.Set(x)
-	res := c.round(d, &z)
+	res := c.round(d, z)
 	res, err := c.goError(res)
 	d.Negative = neg
 
 	// Set z = d^3 to check for exactness.
-	ed.Mul(&z, d, d)
-	ed.Mul(&z, &z, d)
+	ed.Mul(z, d, d)
+	ed.Mul(z, z, d)
 
 	if err := ed.Err(); err != nil {
 		return 0, err
 	}
 
 	// Result is exact
-	if z0.Cmp(&z) == 0 {
+	if z0.Cmp(z) == 0 {
 		return 0, nil
 	}
 	return res, err
@@ -691,12 +1133,18 @@ func (c *Context) Ln(d, x *Decimal) (Condition, error) {
 	// series/iterations add up.
 	p := c.Precision + 2
 
-	nc := c.WithPrecision(p)
+	var local lnWorkspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.ln
+	}
+	nc := c.derivedContext(workspaceLn, c, p)
 	nc.Rounding = RoundHalfEven
 	ed := MakeErrDecimal(nc)
 
-	var tmp1, tmp2, tmp3, tmp4, z, resAdjust Decimal
+	tmp1, tmp2, tmp3, tmp4, z, resAdjust := &w.tmp1, &w.tmp2, &w.tmp3, &w.tmp4, &w.z, &w.resAdjust
 	z.Set(x)
+	resAdjust.SetFinite(0, 0)
 
 	// To get an initial estimate, we first reduce the input range to the interval
 	// [0.1, 1) by changing the exponent, and later adjust the result by a
@@ -721,13 +1169,13 @@ func (c *Context) Ln(d, x *Decimal) (Condition, error) {
 	// instead (which converges very rapidly in this range).
 
 	// tmp1 = z - 1
-	ed.Sub(&tmp1, &z, decimalOne)
+	ed.Sub(tmp1, z, decimalOne)
 	// tmp3 = 0.1
 	tmp3.SetFinite(1, -1)
 
 	usePowerSeries := false
 
-	if tmp2.Abs(&tmp1).Cmp(&tmp3) <= 0 {
+	if tmp2.Abs(tmp1).Cmp(tmp3) <= 0 {
 		usePowerSeries = true
 	} else {
 		// Reduce input to range [0.1, 1).
@@ -738,12 +1186,12 @@ func (c *Context) Ln(d, x *Decimal) (Condition, error) {
 		//   ln(10^expDelta) = expDelta * ln(10)
 		// to the result.
 		resAdjust.setCoefficient(int64(expDelta))
-		ed.Mul(&resAdjust, &resAdjust, decimalLn10.get(p))
+		ed.Mul(resAdjust, resAdjust, decimalLn10.get(p))
 
 		// tmp1 = z - 1
-		ed.Sub(&tmp1, &z, decimalOne)
+		ed.Sub(tmp1, z, decimalOne)
 
-		if tmp2.Abs(&tmp1).Cmp(&tmp3) <= 0 {
+		if tmp2.Abs(tmp1).Cmp(tmp3) <= 0 {
 			usePowerSeries = true
 		} else {
 			// Compute an initial estimate using floats.
@@ -768,32 +1216,31 @@ func (c *Context) Ln(d, x *Decimal) (Condition, error) {
 		// tmp1 is already x
 
 		// tmp3 = x + 2
-		ed.Add(&tmp3, &tmp1, decimalTwo)
+		ed.Add(tmp3, tmp1, decimalTwo)
 
 		// tmp2 = (x / (x+2))
-		ed.Quo(&tmp2, &tmp1, &tmp3)
+		ed.Quo(tmp2, tmp1, tmp3)
 
 		// tmp1 = tmp3 = 2 * (x / (x+2))
-		ed.Add(&tmp3, &tmp2, &tmp2)
-		tmp1.Set(&tmp3)
+		ed.Add(tmp3, tmp2, tmp2)
+		tmp1.Set(tmp3)
 
-		var eps Decimal
-		eps.Coeff.Set(bigOne)
-		eps.Exponent = -int32(p)
+		eps := &w.eps
+		eps.SetFinite(1, -int32(p))
 		for n := 1; ; n++ {
 
 			// tmp3 *= (x / (x+2))^2
-			ed.Mul(&tmp3, &tmp3, &tmp2)
-			ed.Mul(&tmp3, &tmp3, &tmp2)
+			ed.Mul(tmp3, tmp3, tmp2)
+			ed.Mul(tmp3, tmp3, tmp2)
 
 			// tmp4 = 2n+1
 			tmp4.SetFinite(int64(2*n+1), 0)
 
-			ed.Quo(&tmp4, &tmp3, &tmp4)
+			ed.Quo(tmp4, tmp3, tmp4)
 
-			ed.Add(&tmp1, &tmp1, &tmp4)
+			ed.Add(tmp1, tmp1, tmp4)
 
-			if tmp4.Abs(&tmp4).Cmp(&eps) <= 0 {
+			if tmp4.Abs(tmp4).Cmp(eps) <= 0 {
 				break
 			}
 		}
@@ -801,28 +1248,28 @@ func (c *Context) Ln(d, x *Decimal) (Condition, error) {
 		// Use Halley's Iteration.
 		// We use a bit more precision than the context asks for in newLoop because
 		// this is not the final result.
-		for loop := nc.newLoop("ln", x, c.Precision+1, 1); ; {
+		for loop := nc.initLoop(&w.loop, "ln", x, c.Precision+1, 1); ; {
 			// tmp1 = a_n (either from initial estimate or last iteration)
 
 			// tmp2 = exp(a_n)
-			ed.Exp(&tmp2, &tmp1)
+			ed.Exp(tmp2, tmp1)
 
 			// tmp3 = exp(a_n) - z
-			ed.Sub(&tmp3, &tmp2, &z)
+			ed.Sub(tmp3, tmp2, z)
 
 			// tmp3 = 2 * (exp(a_n) - z)
-			ed.Add(&tmp3, &tmp3, &tmp3)
+			ed.Add(tmp3, tmp3, tmp3)
 
 			// tmp4 = exp(a_n) + z
-			ed.Add(&tmp4, &tmp2, &z)
+			ed.Add(tmp4, tmp2, z)
 
 			// tmp2 = 2 * (exp(a_n) - z) / (exp(a_n) + z)
-			ed.Quo(&tmp2, &tmp3, &tmp4)
+			ed.Quo(tmp2, tmp3, tmp4)
 
 			// tmp1 = a_(n+1) = a_n - 2 * (exp(a_n) - z) / (exp(a_n) + z)
-			ed.Sub(&tmp1, &tmp1, &tmp2)
+			ed.Sub(tmp1, tmp1, tmp2)
 
-			if done, err := loop.done(&tmp1); err != nil {
+			if done, err := loop.done(tmp1); err != nil {
 				return 0, err
 			} else if done {
 				break
@@ -834,12 +1281,12 @@ func (c *Context) Ln(d, x *Decimal) (Condition, error) {
 	}
 
 	// Apply the adjustment due to the initial rescaling.
-	ed.Add(&tmp1, &tmp1, &resAdjust)
+	ed.Add(tmp1, tmp1, resAdjust)
 
 	if err := ed.Err(); err != nil {
 		return 0, err
 	}
-	res := c.round(d, &tmp1)
+	res := c.round(d, tmp1)
 	res |= Inexact
 	return c.goError(res)
 }
@@ -853,16 +1300,21 @@ func (c *Context) Log10(d, x *Decimal) (Condition, error) {
 	// TODO(mjibson): This is exact under some conditions.
 	res := Inexact
 
-	nc := BaseContext.WithPrecision(c.Precision + 2)
+	var local log10Workspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.log10
+	}
+	nc := c.derivedContext(workspaceLog10, &BaseContext, c.Precision+2)
 	nc.Rounding = RoundHalfEven
-	var z Decimal
-	_, err := nc.Ln(&z, x)
+	z := &w.z
+	_, err := nc.Ln(z, x)
 	if err != nil {
 		return 0, fmt.Errorf("ln: %w", err)
 	}
 	nc.Precision = c.Precision
 
-	qr, err := nc.Mul(d, &z, decimalInvLn10.get(c.Precision+2))
+	qr, err := nc.Mul(d, z, decimalInvLn10.get(c.Precision+2))
 	if err != nil {
 		return 0, err
 	}
@@ -900,7 +1352,12 @@ func (c *Context) Exp(d, x *Decimal) (Condition, error) {
 
 	// Stage 1
 	cp := c.Precision
-	var tmp1 Decimal
+	var local expWorkspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.exp
+	}
+	tmp1, tmp2 := &w.tmp1, &w.tmp2
 	tmp1.Abs(x)
 	if f, err := tmp1.Float64(); err == nil {
 		// This algorithm doesn't work if currentprecision*23 < |x|. Attempt to
@@ -910,10 +1367,9 @@ func (c *Context) Exp(d, x *Decimal) (Condition, error) {
 			cp = uint32(math.Ceil(ncp))
 		}
 	}
-	var tmp2 Decimal
 	tmp2.SetInt64(int64(cp) * 23)
 	// if abs(x) > 23*currentprecision; assert false
-	if tmp1.Cmp(&tmp2) > 0 {
+	if tmp1.Cmp(tmp2) > 0 {
 		res |= Overflow
 		if x.Sign() < 0 {
 			res = res.negateOverflowFlags()
@@ -926,7 +1382,7 @@ func (c *Context) Exp(d, x *Decimal) (Condition, error) {
 	}
 	// if abs(x) <= setexp(.9, -currentprecision); then result 1
 	tmp2.SetFinite(9, int32(-cp)-1)
-	if tmp1.Cmp(&tmp2) <= 0 {
+	if tmp1.Cmp(tmp2) <= 0 {
 		d.Set(decimalOne)
 		return c.goError(res)
 	}
@@ -937,15 +1393,15 @@ func (c *Context) Exp(d, x *Decimal) (Condition, error) {
 	if t < 0 {
 		t = 0
 	}
-	var k, r Decimal
+	k, r := &w.k, &w.r
 	k.SetFinite(1, t)
-	nc := c.WithPrecision(cp)
+	nc := c.derivedContext(workspaceExp, c, cp)
 	nc.Rounding = RoundHalfEven
-	if _, err := nc.Quo(&r, x, &k); err != nil {
+	if _, err := nc.Quo(r, x, k); err != nil {
 		return 0, fmt.Errorf("Quo: %w", err)
 	}
-	var ra Decimal
-	ra.Abs(&r)
+	ra := &w.ra
+	ra.Abs(r)
 	p := int64(cp) + int64(t) + 2
 
 	// Stage 3
@@ -963,29 +1419,28 @@ func (c *Context) Exp(d, x *Decimal) (Condition, error) {
 	// Stage 4
 	nc.Precision = uint32(p)
 	ed := MakeErrDecimal(nc)
-	var sum Decimal
+	sum := &w.sum
 	sum.SetInt64(1)
 	tmp2.Exponent = 0
 	for i := n - 1; i > 0; i-- {
 		tmp2.setCoefficient(i)
 		// tmp1 = r / i
-		ed.Quo(&tmp1, &r, &tmp2)
+		ed.Quo(tmp1, r, tmp2)
 		// sum = sum * r / i
-		ed.Mul(&sum, &tmp1, &sum)
+		ed.Mul(sum, tmp1, sum)
 		// sum = sum + 1
-		ed.Add(&sum, &sum, decimalOne)
+		ed.Add(sum, sum, decimalOne)
 	}
 	if err != ed.Err() {
 		return 0, err
 	}
 
 	// sum ** k
-	var tmpE BigInt
-	ki, err := exp10(int64(t), &tmpE)
+	ki, err := exp10(int64(t), &w.tmpE)
 	if err != nil {
 		return 0, fmt.Errorf("ki: %w", err)
 	}
-	ires, err := nc.integerPower(d, &sum, ki)
+	ires, err := nc.integerPower(d, sum, ki)
 	if err != nil {
 		return 0, fmt.Errorf("integer power: %w", err)
 	}
@@ -999,28 +1454,37 @@ func (c *Context) Exp(d, x *Decimal) (Condition, error) {
 func (c *Context) integerPower(d, x *Decimal, y *BigInt) (Condition, error) {
 	// See: https://en.wikipedia.org/wiki/Exponentiation_by_squaring.
 
-	var b BigInt
+	var local integerPowerWorkspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.integerPower
+	}
+	b := &w.b
 	b.Set(y)
 	neg := b.Sign() < 0
 	if neg {
-		b.Abs(&b)
+		b.Abs(b)
 	}
 
-	var n Decimal
+	n := &w.n
 	n.Set(x)
 	z := d
 	z.Set(decimalOne)
-	ed := MakeErrDecimal(c)
+	// Intermediate results can be subnormal, which is only final after the
+	// caller rounds the result.
+	ic := *c
+	ic.Traps &^= Subnormal | Underflow
+	ed := MakeErrDecimal(&ic)
 	for b.Sign() > 0 {
 		if b.Bit(0) == 1 {
-			ed.Mul(z, z, &n)
+			ed.Mul(z, z, n)
 		}
-		b.Rsh(&b, 1)
+		b.Rsh(b, 1)
 
 		// Only compute the next n if we are going to use it. Otherwise n can overflow
 		// on the last iteration causing this to error.
 		if b.Sign() > 0 {
-			ed.Mul(&n, &n, &n)
+			ed.Mul(n, n, n)
 		}
 		if err := ed.Err(); err != nil {
 			// In the negative case, convert overflow to underflow.
@@ -1032,19 +1496,44 @@ func (c *Context) integerPower(d, x *Decimal, y *BigInt) (Condition, error) {
 	}
 
 	if neg {
-		ed.Quo(z, decimalOne, z)
+		// The reciprocal of a subnormal overflows, which the caller reports
+		// when it rounds the result.
+		ed.Flags &^= Subnormal | Underflow | Clamped
+		ic.Traps &^= Overflow
+		if z.IsZero() {
+			z.Form = Infinite
+			z.Exponent = 0
+			ed.Flags |= Overflow | Inexact | Rounded
+		} else {
+			ed.Quo(z, decimalOne, z)
+		}
 	}
 	return ed.Flags, ed.Err()
 }
 
+// mathOutOfRange reports whether the adjusted exponent of x is beyond the
+// limits of decNumber for the operands of its mathematical functions.
+func mathOutOfRange(x *Decimal) bool {
+	if x.Form != Finite || x.IsZero() {
+		return false
+	}
+	adj := int64(x.Exponent) + x.NumDigits() - 1
+	return adj > maxMathExponent || adj < 1-2*maxMathExponent
+}
+
 // Pow sets d = x**y.
 func (c *Context) Pow(d, x, y *Decimal) (Condition, error) {
 	if c.shouldSetAsNaN(x, y) {
 		return c.setAsNaN(d, x, y)
 	}
 
-	var integ, frac Decimal
-	y.Modf(&integ, &frac)
+	var local powWorkspace
+	w := &local
+	if c.Workspace != nil {
+		w = &c.Workspace.pow
+	}
+	integ, frac := &w.integ, &w.frac
+	y.Modf(integ, frac)
 	yIsInt := frac.IsZero()
 	neg := x.Negative && y.Form == Finite && yIsInt && integ.Coeff.Bit(0) == 1 && integ.Exponent == 0
 
@@ -1065,7 +1554,7 @@ func (c *Context) Pow(d, x, y *Decimal) (Condition, error) {
 	}
 
 	// Check if y is of type int.
-	var tmp Decimal
+	tmp := &w.tmp
 	tmp.Abs(y)
 
 	xs := x.Sign()
@@ -1095,6 +1584,15 @@ func (c *Context) Pow(d, x, y *Decimal) (Condition, error) {
 		return c.goError(InvalidOperation)
 	}
 
+	// As in decNumber, unless y is an integer of at most 9 digits, operands
+	// with very large or small exponents are invalid.
+	if !yIsInt || y.NumDigits()+int64(y.Exponent) > 9 {
+		if mathOutOfRange(x) || mathOutOfRange(y) {
+			d.Set(decimalNaN)
+			return c.goError(InvalidOperation)
+		}
+	}
+
 	// decNumber sets the precision to be max(x digits, c.Precision) +
 	// len(exponent) + 4. 6 is used as the exponent maximum length.
 	p := c.Precision
@@ -1103,15 +1601,15 @@ func (c *Context) Pow(d, x, y *Decimal) (Condition, error) {
 	}
 	p += 4 + 6
 
-	nc := BaseContext.WithPrecision(p)
+	nc := c.derivedContext(workspacePow, &BaseContext, p)
 
 	z := d
 	if z == x {
-		z = new(Decimal)
+		z = &w.z
 	}
 
 	// If integ.Exponent > 0, we need to add trailing 0s to integ.Coeff.
-	res := c.quantize(&integ, &integ, 0)
+	res := c.quantize(integ, integ, 0)
 	nres, err := nc.integerPower(z, x, integ.setBig(&integ.Coeff))
 	res |= nres
 	if err != nil {
@@ -1127,18 +1625,18 @@ func (c *Context) Pow(d, x, y *Decimal) (Condition, error) {
 	ed := MakeErrDecimal(nc)
 
 	// Compute x**frac(y)
-	ed.Abs(&tmp, x)
-	ed.Ln(&tmp, &tmp)
-	ed.Mul(&tmp, &tmp, &frac)
-	ed.Exp(&tmp, &tmp)
+	ed.Abs(tmp, x)
+	ed.Ln(tmp, tmp)
+	ed.Mul(tmp, tmp, frac)
+	ed.Exp(tmp, tmp)
 
 	// Join integer and frac parts back.
-	ed.Mul(&tmp, z, &tmp)
+	ed.Mul(tmp, z, tmp)
 
 	if err := ed.Err(); err != nil {
 		return ed.Flags, err
 	}
-	res |= c.round(d, &tmp)
+	res |= c.round(d, tmp)
 	d.Negative = neg
 	res |= Inexact
 	return c.goError(res)
@@ -1154,6 +1652,12 @@ func (c *Context) Quantize(d, x *Decimal, exp int32) (Condition, error) {
 		d.Set(decimalNaN)
 		return c.goError(InvalidOperation)
 	}
+	// A coefficient that would be scaled up beyond c.Precision is invalid
+	// without computing it.
+	if !x.IsZero() && x.NumDigits()+int64(x.Exponent)-int64(exp) > int64(c.Precision) {
+		d.Set(decimalNaN)
+		return c.goError(InvalidOperation)
+	}
 	res := c.quantize(d, x, exp)
 	if nd := d.NumDigits(); nd > int64(c.Precision) || exp > c.MaxExponent {
 		res = InvalidOperation
@@ -1169,17 +1673,17 @@ func (c *Context) Quantize(d, x *Decimal, exp int32) (Condition, error) {
 }
 
 func (c *Context) quantize(d, v *Decimal, exp int32) Condition {
-	diff := exp - v.Exponent
+	diff := int64(exp) - int64(v.Exponent)
 	d.Set(v)
 	var res Condition
-	if diff < 0 {
-		if diff < MinExponent {
+	if diff < 0 && !d.IsZero() {
+		if diff < -maxExactScale {
 			return SystemUnderflow | Underflow
 		}
 		var tmpE BigInt
-		d.Coeff.Mul(&d.Coeff, tableExp10(-int64(diff), &tmpE))
+		d.Coeff.Mul(&d.Coeff, tableExp10(-diff, &tmpE))
 	} else if diff > 0 {
-		p := int32(d.NumDigits()) - diff
+		p := d.NumDigits() - diff
 		if p < 0 {
 			if !d.IsZero() {
 				d.Coeff.SetInt64(0)
@@ -1202,7 +1706,7 @@ func (c *Context) quantize(d, v *Decimal, exp int32) Condition {
 			// is guaranteed to not raise underflow, and using 0 instead of exp as the
 			// target eliminates this problem.
 
-			d.Exponent = -diff
+			d.Exponent = int32(-diff)
 			// Round even if nc.Precision == 0.
 			res = nc.Rounding.Round(nc, d, d, false /* disableIfPrecisionZero */)
 			// Adjust for 0.9 -> 1.0 rollover.
@@ -1216,6 +1720,12 @@ func (c *Context) quantize(d, v *Decimal, exp int32) Condition {
 }
 
 func (c *Context) toIntegral(d, x *Decimal) Condition {
+	if x.Exponent > maxExactScale {
+		// Already an integer, which keeps its exponent as in decNumber
+		// rather than allocating its digits.
+		d.Set(x)
+		return 0
+	}
 	res := c.quantize(d, x, 0)
 	return res
 }
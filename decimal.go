@@ -18,7 +18,6 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"unsafe"
 
 	"database/sql/driver"
@@ -63,17 +62,22 @@ var (
 //go:generate stringer -type=Form
 
 const (
-	// TODO(mjibson): MaxExponent is set because both upscale and Round
-	// perform a calculation of 10^x, where x is an exponent. This is done by
-	// big.Int.Exp. This restriction could be lifted if better algorithms were
-	// determined during upscale and Round that don't need to perform Exp.
-
-	// MaxExponent is the highest exponent supported. Exponents near this range will
-	// perform very slowly (many seconds per operation).
-	MaxExponent = 100000
+	// MaxExponent is the highest exponent supported, as in decNumber. Rounded
+	// operations compare the magnitudes of their operands before aligning them,
+	// so a negligible operand does not require a large power of ten. Exact
+	// operations, such as Add with a Precision of 0, must align their operands
+	// and return an error if that requires a power of ten above 10^100000.
+	MaxExponent = 999999999
 	// MinExponent is the lowest exponent supported with the same limitations as
 	// MaxExponent.
 	MinExponent = -MaxExponent
+
+	// maxExactScale is the largest number of digits by which a coefficient is
+	// scaled up to align or rescale it exactly.
+	maxExactScale = 100000
+	// maxMathExponent bounds the adjusted exponents of the operands of Pow,
+	// as DEC_MAX_MATH does in decNumber.
+	maxMathExponent = 999999
 )
 
 // New creates a new decimal with the given coefficient and exponent.
@@ -95,75 +99,6 @@ func NewWithBigInt(coeff *BigInt, exponent int32) *Decimal {
 	return d
 }
 
-func consumePrefix(s, prefix string) (string, bool) {
-	if strings.HasPrefix(s, prefix) {
-		return s[len(prefix):], true
-	}
-	return s, false
-}
-
-func (d *Decimal) setString(c *Context, s string) (Condition, error) {
-	orig := s
-	s, d.Negative = consumePrefix(s, "-")
-	if !d.Negative {
-		s, _ = consumePrefix(s, "+")
-	}
-	s = strings.ToLower(s)
-	d.Exponent = 0
-	d.Coeff.SetInt64(0)
-	// Until there are no parse errors, leave as NaN.
-	d.Form = NaN
-	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
-		return 0, fmt.Errorf("could not parse: %s", orig)
-	}
-	switch s {
-	case "infinity", "inf":
-		d.Form = Infinite
-		return 0, nil
-	}
-	isNaN := false
-	s, consumed := consumePrefix(s, "nan")
-	if consumed {
-		isNaN = true
-	}
-	s, consumed = consumePrefix(s, "snan")
-	if consumed {
-		isNaN = true
-		d.Form = NaNSignaling
-	}
-	if isNaN {
-		if s != "" {
-			// We ignore these digits, but must verify them.
-			_, err := strconv.ParseUint(s, 10, 64)
-			if err != nil {
-				return 0, fmt.Errorf("parse payload: %s: %w", s, err)
-			}
-		}
-		return 0, nil
-	}
-
-	exps := make([]int64, 0, 2)
-	if i := strings.IndexByte(s, 'e'); i >= 0 {
-		exp, err := strconv.ParseInt(s[i+1:], 10, 32)
-		if err != nil {
-			return 0, fmt.Errorf("parse exponent: %s: %w", s[i+1:], err)
-		}
-		exps = append(exps, exp)
-		s = s[:i]
-	}
-	if i := strings.IndexByte(s, '.'); i >= 0 {
-		exp := int64(len(s) - i - 1)
-		exps = append(exps, -exp)
-		s = s[:i] + s[i+1:]
-	}
-	if _, ok := d.Coeff.SetString(s, 10); !ok {
-		return 0, fmt.Errorf("parse mantissa: %s", s)
-	}
-	// No parse errors, can now flag as finite.
-	d.Form = Finite
-	return c.goError(d.setExponent(c, unknownNumDigits, 0, exps...))
-}
-
 // NewFromString creates a new decimal from s. It has no restrictions on
 // exponents or precision.
 func NewFromString(s string) (*Decimal, Condition, error) {
@@ -176,6 +111,12 @@ func (d *Decimal) SetString(s string) (*Decimal, Condition, error) {
 	return BaseContext.SetString(d, s)
 }
 
+// SetStringBytes is like SetString, but parses b without converting it to a
+// string. It does not allocate for values with at most 19 coefficient digits.
+func (d *Decimal) SetStringBytes(b []byte) (*Decimal, Condition, error) {
+	return BaseContext.SetStringBytes(d, b)
+}
+
 // NewFromString creates a new decimal from s. The returned Decimal has its
 // exponents restricted by the context and its value rounded if it contains more
 // digits than the context's precision.
@@ -188,13 +129,13 @@ func (c *Context) NewFromString(s string) (*Decimal, Condition, error) {
 // restricted by the context and its value rounded if it contains more digits
 // than the context's precision.
 func (c *Context) SetString(d *Decimal, s string) (*Decimal, Condition, error) {
-	res, err := d.setString(c, s)
-	if err != nil {
-		return nil, 0, err
-	}
-	res |= c.round(d, d)
-	_, err = c.goError(res)
-	return d, res, err
+	return c.SetStringOptions(d, s, ParseGDA)
+}
+
+// SetStringBytes is like SetString, but parses b without converting it to a
+// string.
+func (c *Context) SetStringBytes(d *Decimal, b []byte) (*Decimal, Condition, error) {
+	return c.SetStringBytesOptions(d, b, ParseGDA)
 }
 
 // Set sets d's fields to the values of x and returns d.
@@ -239,16 +180,16 @@ func (d *Decimal) setCoefficient(x int64) {
 	d.Form = Finite
 }
 
-// SetFloat64 sets d's Coefficient and Exponent to x and returns d. d will
-// hold the exact value of f.
+// SetFloat64 sets d's Coefficient and Exponent to the shortest decimal that
+// rounds to f, and returns d. SetFloat64Exact gives the exact value of f.
 func (d *Decimal) SetFloat64(f float64) (*Decimal, error) {
 	var buf [32]byte // Avoid most of the allocations in strconv.
-	_, _, err := d.SetString(string(strconv.AppendFloat(buf[:0], f, 'E', -1, 64)))
+	_, _, err := d.SetStringBytes(strconv.AppendFloat(buf[:0], f, 'E', -1, 64))
 	return d, err
 }
 
 // Int64 returns the int64 representation of x. If x cannot be represented in an
-// int64, an error is returned.
+// int64, an error is returned. Context.Int64 rounds away a fractional part.
 func (d *Decimal) Int64() (int64, error) {
 	if d.Form != Finite {
 		return 0, fmt.Errorf("%s is not finite", d.String())
@@ -290,9 +231,12 @@ const (
 	unknownNumDigits = int64(-1)
 )
 
-// setExponent sets d's Exponent to the sum of xs. Each value and the sum
-// of xs must fit within an int32. An error occurs if the sum is outside of
-// the MaxExponent or MinExponent range. nd is the number of digits in d, as
+// setExponent sets d's Exponent to the sum of xs. The sum of xs must fit
+// within an int64. Outside of the limits of c, d overflows or is rounded as a
+// subnormal. An overflow gives Infinity, or the largest finite value with
+// Rounded if the rounding mode of c doesn't round away from it. Within the
+// limits of c, an error occurs if the adjusted exponent is outside of the
+// MaxExponent or MinExponent range. nd is the number of digits in d, as
 // computed by NumDigits. Callers can pass unknownNumDigits to indicate that
 // they have not yet computed this digit count, in which case setExponent will
 // do so. res is any Condition previously set for this operation, which can
@@ -300,45 +244,44 @@ const (
 func (d *Decimal) setExponent(c *Context, nd int64, res Condition, xs ...int64) Condition {
 	var sum int64
 	for _, x := range xs {
-		if x > MaxExponent {
-			return SystemOverflow | Overflow
-		}
-		if x < MinExponent {
-			return SystemUnderflow | Underflow
-		}
 		sum += x
 	}
-	r := int32(sum)
 
 	if nd == unknownNumDigits {
-		nd = d.NumDigits()
+		nd = c.numDigits(&d.Coeff)
 	}
 	// adj is the adjusted exponent: exponent + clength - 1
 	adj := sum + nd - 1
-	// Make sure it is less than the system limits.
-	if adj > MaxExponent {
+	// Make sure it is within the system limits.
+	if adj > MaxExponent && adj <= int64(c.MaxExponent) {
 		return SystemOverflow | Overflow
 	}
-	if adj < MinExponent {
+	if adj < MinExponent && adj >= int64(c.MinExponent) {
 		return SystemUnderflow | Underflow
 	}
-	v := int32(adj)
+	r := sum
 
 	// d is subnormal.
-	if v < c.MinExponent {
+	if adj < int64(c.MinExponent) {
 		if !d.IsZero() {
 			res |= Subnormal
 		}
-		Etiny := c.MinExponent - (int32(c.Precision) - 1)
+		Etiny := int64(c.MinExponent) - (int64(c.Precision) - 1)
 		// Only need to round if exponent < Etiny.
 		if r < Etiny {
 			// We need to take off (r - Etiny) digits. Split up d.Coeff into integer and
 			// fractional parts and do operations similar Round. We avoid calling Round
 			// directly because it calls setExponent and modifies the result's exponent
-			// and coeff in ways that would be wrong here.
+			// and coeff in ways that would be wrong here. Taking off more than nd+1
+			// digits rounds as taking off nd+1 does.
+			k := Etiny - r
+			if k > nd+1 {
+				k = nd + 1
+			}
 			var tmp Decimal
+			tmp.Negative = d.Negative
 			tmp.Coeff.Set(&d.Coeff)
-			tmp.Exponent = r - Etiny
+			tmp.Exponent = int32(-k)
 			var integ, frac Decimal
 			tmp.Modf(&integ, &frac)
 			frac.Abs(&frac)
@@ -355,13 +298,26 @@ func (d *Decimal) setExponent(c *Context, nd int64, res Condition, xs ...int64)
 			d.Coeff.Set(&integ.Coeff)
 			res |= Rounded
 		}
-	} else if v > c.MaxExponent {
+	} else if adj > int64(c.MaxExponent) {
 		if d.IsZero() {
 			res |= Clamped
-			r = c.MaxExponent
+			r = int64(c.MaxExponent)
 		} else {
-			res |= Overflow | Inexact
-			d.Form = Infinite
+			res |= Overflow | Inexact | Rounded
+			// Rounding that would not round up a value just beyond the
+			// largest finite number, whose last digit is 9, gives that
+			// number instead of Infinity.
+			var nine BigInt
+			nine.SetInt64(9)
+			if c.Precision == 0 || c.Rounding.ShouldAddOne(&nine, d.Negative, 1) {
+				d.Form = Infinite
+				d.Coeff.SetInt64(0)
+				r = 0
+			} else {
+				var tmpE BigInt
+				d.Coeff.Sub(tableExp10(int64(c.Precision), &tmpE), bigOne)
+				r = int64(c.MaxExponent) - int64(c.Precision) + 1
+			}
 		}
 	}
 
@@ -369,14 +325,15 @@ func (d *Decimal) setExponent(c *Context, nd int64, res Condition, xs ...int64)
 		res |= Underflow
 	}
 
-	d.Exponent = r
+	d.Exponent = int32(r)
 	return res
 }
 
 // upscale converts a and b to BigInts with the same scaling. It returns
 // them with this scaling, along with the scaling. An error can be produced
-// if the resulting scale factor is out of range. The tmp argument must be
-// provided and can be (but won't always be) one of the return values.
+// if the resulting scale factor is larger than maxExactScale. The tmp
+// argument must be provided and can be (but won't always be) one of the
+// return values.
 func upscale(a, b *Decimal, tmp *BigInt) (*BigInt, *BigInt, int32, error) {
 	if a.Exponent == b.Exponent {
 		return &a.Coeff, &b.Coeff, a.Exponent, nil
@@ -387,9 +344,7 @@ func upscale(a, b *Decimal, tmp *BigInt) (*BigInt, *BigInt, int32, error) {
 		b, a = a, b
 	}
 	s := int64(a.Exponent) - int64(b.Exponent)
-	// TODO(mjibson): figure out a better way to upscale numbers with highly
-	// differing exponents.
-	if s > MaxExponent {
+	if s > maxExactScale {
 		return nil, nil, 0, errors.New(errExponentOutOfRangeStr)
 	}
 	x := tmp
@@ -555,6 +510,12 @@ func (d *Decimal) Cmp(x *Decimal) int {
 		return cmp
 	}
 
+	if a, ok := d.decimal128(); ok {
+		if b, ok := x.decimal128(); ok {
+			return a.Cmp(b)
+		}
+	}
+
 	// Next compare adjusted exponents.
 	dn := d.NumDigits() + int64(d.Exponent)
 	xn := x.NumDigits() + int64(x.Exponent)
@@ -769,7 +730,7 @@ func (d Decimal) Value() (driver.Value, error) {
 func (d *Decimal) Scan(src interface{}) error {
 	switch src := src.(type) {
 	case []byte:
-		_, _, err := d.SetString(string(src))
+		_, _, err := d.SetStringBytes(src)
 		return err
 	case string:
 		_, _, err := d.SetString(src)
@@ -787,7 +748,7 @@ func (d *Decimal) Scan(src interface{}) error {
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
 func (d *Decimal) UnmarshalText(b []byte) error {
-	_, _, err := d.SetString(string(b))
+	_, _, err := d.SetStringBytes(b)
 	return err
 }
 
@@ -799,7 +760,7 @@ func (d *Decimal) MarshalText() ([]byte, error) {
 	return []byte(d.String()), nil
 }
 
-// NullDecimal represents a string that may be null. NullDecimal implements
+// NullDecimal represents a decimal that may be null. NullDecimal implements
 // the database/sql.Scanner interface so it can be used as a scan destination:
 //
 //	var d NullDecimal
@@ -810,6 +771,9 @@ func (d *Decimal) MarshalText() ([]byte, error) {
 //	} else {
 //	   // NULL value
 //	}
+//
+// NullDecimal also implements JSON, text and binary marshalling, which encode
+// NULL as null or an empty value.
 type NullDecimal struct {
 	Decimal Decimal
 	Valid   bool // Valid is true if Decimal is not NULL
@@ -0,0 +1,707 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+)
+
+// MaxDecimal128Digits is the number of significant digits a Decimal128 can
+// hold.
+const MaxDecimal128Digits = 38
+
+// Decimal128 is a compact, fixed-size finite decimal. Its value is:
+//
+//	Negative × Coeff × 10**Exponent
+//
+// where Coeff is an unsigned integer of at most 38 digits stored in 128 bits.
+// This is the range of the NUMERIC(38,s) type of many databases. Decimal128
+// is comparable, but == compares representations: 1.0 and 1.00 are not
+// equal. Use Cmp to compare numerical values.
+//
+// The Context methods with a 128 suffix operate on Decimal128s. They produce
+// the same results and Conditions as the corresponding Decimal methods. When
+// the operands and result are small enough they compute in 128-bit integer
+// arithmetic without allocating, and otherwise fall back to Decimal. An
+// error is returned if the result cannot be represented as a Decimal128; a
+// Context with a Precision of at most 38 ensures that finite results always
+// can.
+type Decimal128 struct {
+	coeff    uint128
+	exponent int32
+	negative bool
+}
+
+// NewDecimal128 returns a Decimal128 with the given coefficient and exponent.
+func NewDecimal128(coeff int64, exponent int32) Decimal128 {
+	d := Decimal128{exponent: exponent, negative: coeff < 0}
+	if coeff < 0 {
+		d.coeff.lo = uint64(-coeff)
+	} else {
+		d.coeff.lo = uint64(coeff)
+	}
+	return d
+}
+
+// Exponent returns d's exponent.
+func (d Decimal128) Exponent() int32 {
+	return d.exponent
+}
+
+// Negative returns whether d's sign is negative. This is true for -0.
+func (d Decimal128) Negative() bool {
+	return d.negative
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 or -0, and +1 if d > 0.
+func (d Decimal128) Sign() int {
+	if d.coeff.isZero() {
+		return 0
+	}
+	if d.negative {
+		return -1
+	}
+	return 1
+}
+
+// IsZero returns true if d == 0 or -0.
+func (d Decimal128) IsZero() bool {
+	return d.coeff.isZero()
+}
+
+// Neg returns -d.
+func (d Decimal128) Neg() Decimal128 {
+	if d.IsZero() {
+		d.negative = false
+	} else {
+		d.negative = !d.negative
+	}
+	return d
+}
+
+// Abs returns |d|.
+func (d Decimal128) Abs() Decimal128 {
+	d.negative = false
+	return d
+}
+
+// String formats d like (*Decimal).String.
+func (d Decimal128) String() string {
+	var x Decimal
+	return x.SetDecimal128(d).String()
+}
+
+// Cmp compares d and x and returns:
+//
+//	-1 if d <  x
+//	 0 if d == x
+//	+1 if d >  x
+func (d Decimal128) Cmp(x Decimal128) int {
+	ds, xs := d.Sign(), x.Sign()
+	if ds != xs {
+		if ds < xs {
+			return -1
+		}
+		return 1
+	}
+	if ds == 0 {
+		return 0
+	}
+	a, b := d.coeff, x.coeff
+	// Align the coefficients. If the scaled coefficient overflows it is
+	// larger than the other, which fits in 128 bits.
+	cmp := 0
+	if d.exponent > x.exponent {
+		var ok bool
+		if a, ok = a.mulPow10(int64(d.exponent) - int64(x.exponent)); !ok {
+			cmp = 1
+		}
+	} else if d.exponent < x.exponent {
+		var ok bool
+		if b, ok = b.mulPow10(int64(x.exponent) - int64(d.exponent)); !ok {
+			cmp = -1
+		}
+	}
+	if cmp == 0 {
+		cmp = a.cmp(b)
+	}
+	if ds < 0 {
+		cmp = -cmp
+	}
+	return cmp
+}
+
+// SetDecimal128 sets d to x and returns d.
+func (d *Decimal) SetDecimal128(x Decimal128) *Decimal {
+	d.Form = Finite
+	d.Negative = x.negative
+	d.Exponent = x.exponent
+	x.coeff.bigInt(&d.Coeff)
+	return d
+}
+
+var errDecimal128Range = errors.New("value does not fit in a Decimal128")
+
+// Decimal128 returns d as a Decimal128. An error is returned if d is not
+// finite or its coefficient has more than 38 digits.
+func (d *Decimal) Decimal128() (Decimal128, error) {
+	if d.Form != Finite {
+		return Decimal128{}, fmt.Errorf("%s: %w", d.String(), errDecimal128Range)
+	}
+	r := Decimal128{exponent: d.Exponent, negative: d.Negative}
+	var ok bool
+	if r.coeff, ok = uint128FromBigInt(&d.Coeff); !ok {
+		return Decimal128{}, fmt.Errorf("%s: %w", d.String(), errDecimal128Range)
+	}
+	return r, nil
+}
+
+// decimal128 returns d as a Decimal128 if it is finite and its coefficient
+// fits. It is the allocation-free form of Decimal128 used by the fast paths of
+// the Decimal operations.
+func (d *Decimal) decimal128() (Decimal128, bool) {
+	if d.Form != Finite {
+		return Decimal128{}, false
+	}
+	u, ok := uint128FromBigInt(&d.Coeff)
+	return Decimal128{coeff: u, exponent: d.Exponent, negative: d.Negative}, ok
+}
+
+// Add128 sets d to the sum x+y.
+func (c *Context) Add128(d *Decimal128, x, y Decimal128) (Condition, error) {
+	return c.add128(d, x, y, false)
+}
+
+// Sub128 sets d to the difference x-y.
+func (c *Context) Sub128(d *Decimal128, x, y Decimal128) (Condition, error) {
+	return c.add128(d, x, y, true)
+}
+
+func (c *Context) add128(d *Decimal128, x, y Decimal128, subtract bool) (Condition, error) {
+	if r, res, ok := c.addUint128(x, y, subtract); ok {
+		*d = r
+		return c.goError(res)
+	}
+	if subtract {
+		return c.fallback128(d, x, y, (*Context).Sub)
+	}
+	return c.fallback128(d, x, y, (*Context).Add)
+}
+
+// addUint128 returns x+y, or x-y if subtract is true, computed in 128-bit
+// arithmetic. It returns false if the aligned coefficients or the result do
+// not fit, in which case the caller must compute with Decimal.
+func (c *Context) addUint128(x, y Decimal128, subtract bool) (Decimal128, Condition, bool) {
+	xn := x.negative
+	yn := y.negative != subtract
+	a, b, exp, ok := align128(x, y)
+	if !ok {
+		return Decimal128{}, 0, false
+	}
+	r := Decimal128{exponent: exp, negative: xn}
+	if xn == yn {
+		if r.coeff, ok = a.add(b); !ok {
+			return Decimal128{}, 0, false
+		}
+	} else if a.cmp(b) >= 0 {
+		r.coeff = a.sub(b)
+	} else {
+		r.coeff = b.sub(a)
+		r.negative = !r.negative
+	}
+	if r.coeff.isZero() && xn != yn {
+		r.negative = c.Rounding == RoundFloor
+	}
+	res, ok := c.round128(&r)
+	return r, res, ok
+}
+
+// align128 returns the coefficients of x and y scaled to the smaller of their
+// exponents, like upscale, and that exponent. It returns false if a scaled
+// coefficient does not fit in 128 bits or upscale would refuse the exponents.
+func align128(x, y Decimal128) (a, b uint128, exp int32, ok bool) {
+	a, b = x.coeff, y.coeff
+	exp = x.exponent
+	ok = true
+	if s := int64(x.exponent) - int64(y.exponent); s > MaxExponent || s < -MaxExponent {
+		return uint128{}, uint128{}, 0, false
+	} else if s > 0 {
+		a, ok = a.mulPow10(s)
+		exp = y.exponent
+	} else if s < 0 {
+		b, ok = b.mulPow10(-s)
+	}
+	return a, b, exp, ok
+}
+
+// Mul128 sets d to the product x*y.
+func (c *Context) Mul128(d *Decimal128, x, y Decimal128) (Condition, error) {
+	if r, res, ok := c.mulUint128(x, y); ok {
+		*d = r
+		return c.goError(res)
+	}
+	return c.fallback128(d, x, y, (*Context).Mul)
+}
+
+// mulUint128 returns x*y computed in 128-bit arithmetic. It returns false if
+// the product does not fit, in which case the caller must compute with
+// Decimal.
+func (c *Context) mulUint128(x, y Decimal128) (Decimal128, Condition, bool) {
+	if x.exponent > MaxExponent || x.exponent < MinExponent ||
+		y.exponent > MaxExponent || y.exponent < MinExponent {
+		return Decimal128{}, 0, false
+	}
+	p, ok := x.coeff.mul(y.coeff)
+	if !ok {
+		return Decimal128{}, 0, false
+	}
+	exp := int64(x.exponent) + int64(y.exponent)
+	r := Decimal128{coeff: p, exponent: int32(exp), negative: x.negative != y.negative}
+	res, ok := c.round128(&r)
+	return r, res, ok
+}
+
+// Quo128 sets d to the quotient x/y for y != 0. c.Precision must be > 0.
+func (c *Context) Quo128(d *Decimal128, x, y Decimal128) (Condition, error) {
+	if r, res, ok := c.quo128(x, y); ok {
+		*d = r
+		return c.goError(res)
+	}
+	return c.fallback128(d, x, y, (*Context).Quo)
+}
+
+// quo128 implements the same algorithm as Quo using 128-bit arithmetic. It
+// returns false if the operands or result are outside of the range it
+// supports, in which case the caller must fall back to Quo.
+func (c *Context) quo128(x, y Decimal128) (Decimal128, Condition, bool) {
+	// Long division below multiplies the remainder, which is less than the
+	// divisor, by 10. Limit the digits of the operands so this can't overflow.
+	const maxDigits = MaxDecimal128Digits - 1
+	if c.Precision == 0 || c.Precision > MaxDecimal128Digits || x.IsZero() || y.IsZero() {
+		return Decimal128{}, 0, false
+	}
+	dividend, divisor := x.coeff, y.coeff
+	ndDividend, ndDivisor := dividend.numDigits(), divisor.numDigits()
+	if ndDividend > maxDigits || ndDivisor > maxDigits {
+		return Decimal128{}, 0, false
+	}
+	shift := int64(x.exponent) - int64(y.exponent)
+
+	// Adjust the operands so that divisor <= dividend < 10*divisor.
+	ndDiff := ndDividend - ndDivisor
+	if ndDiff < 0 {
+		dividend, _ = dividend.mulPow10(-ndDiff)
+	} else if ndDiff > 0 {
+		divisor, _ = divisor.mulPow10(ndDiff)
+	}
+	adjCoeffs := -ndDiff
+	if dividend.cmp(divisor) < 0 {
+		dividend = dividend.mul10()
+		adjCoeffs++
+	}
+
+	// Compute Precision digits of the quotient by long division.
+	var q, rem uint128
+	if divisor.hi == 0 {
+		q, rem = quoDigits64(dividend, divisor.lo, c.Precision)
+	} else {
+		rem = dividend
+		for i := uint32(0); i < c.Precision; i++ {
+			if i > 0 {
+				rem = rem.mul10()
+			}
+			digit := uint64(0)
+			for rem.cmp(divisor) >= 0 {
+				rem = rem.sub(divisor)
+				digit++
+			}
+			q = q.mul10().add64(digit)
+		}
+	}
+	adjExp10 := int64(c.Precision - 1)
+
+	// Quo does not round subnormal results; leave those to it.
+	exp := shift - adjCoeffs - adjExp10
+	if !c.normal128(exp, q.numDigits()) {
+		return Decimal128{}, 0, false
+	}
+	r := Decimal128{coeff: q, negative: x.negative != y.negative}
+	var res Condition
+	if !rem.isZero() {
+		res |= Inexact | Rounded
+		var half int
+		if rem, ok := rem.add(rem); ok {
+			half = rem.cmp(divisor)
+		} else {
+			half = 1
+		}
+		var tmp BigInt
+		if c.Rounding.ShouldAddOne(q.bigInt(&tmp), r.negative, half) {
+			r.coeff = q.add64(1)
+		}
+	}
+	if !c.normal128(exp, r.coeff.numDigits()) {
+		return Decimal128{}, 0, false
+	}
+	r.exponent = int32(exp)
+	return r, res, true
+}
+
+// quoDigits64 returns the first n digits of the quotient u/v and the
+// remainder, that is, u×10^(n-1)/v and u×10^(n-1)%v, where v <= u < 10v. Each
+// step divides a 128-bit value by v and produces up to 19 digits.
+func quoDigits64(u uint128, v uint64, n uint32) (q, rem uint128) {
+	// u < 10v, so the first digit and the remainder fit in 64 bits.
+	q.lo, rem.lo = u.quoRem64(v)
+	for n--; n > 0; {
+		k := n
+		if k > 19 {
+			k = 19
+		}
+		// rem < v, so rem×10^k / v < 10^k fits in 64 bits.
+		hi, lo := bits.Mul64(rem.lo, pow10Uint128[k].lo)
+		var digits uint64
+		digits, rem.lo = bits.Div64(hi, lo, v)
+		q, _ = q.mul64(pow10Uint128[k].lo)
+		q = q.add64(digits)
+		n -= k
+	}
+	return q, rem
+}
+
+// quoInteger128 implements QuoInteger using 128-bit arithmetic. It returns
+// false if the operands do not fit or the result has more than Precision
+// digits, in which case the caller must fall back to QuoInteger.
+func (c *Context) quoInteger128(x, y Decimal128) (Decimal128, bool) {
+	a, b, _, ok := align128(x, y)
+	if !ok || b.isZero() {
+		return Decimal128{}, false
+	}
+	q, _ := a.quoRem(b)
+	if q.numDigits() > int64(c.Precision) {
+		return Decimal128{}, false
+	}
+	return Decimal128{coeff: q, negative: x.negative != y.negative}, true
+}
+
+// rem128 implements Rem using 128-bit arithmetic. It returns false if the
+// operands do not fit, the integer part of the quotient has more than
+// Precision digits, or rounding the remainder falls back.
+func (c *Context) rem128(x, y Decimal128) (Decimal128, Condition, bool) {
+	a, b, exp, ok := align128(x, y)
+	if !ok || b.isZero() {
+		return Decimal128{}, 0, false
+	}
+	q, r := a.quoRem(b)
+	if q.numDigits() > int64(c.Precision) {
+		return Decimal128{}, 0, false
+	}
+	// The sign of the result is the sign of the dividend.
+	d := Decimal128{coeff: r, exponent: exp, negative: x.negative}
+	res, ok := c.round128(&d)
+	return d, res, ok
+}
+
+// Quantize128 adjusts and rounds x as necessary so it is represented with
+// exponent exp and stores the result in d. Quantizing to the exponent -s with
+// a Precision of 38 produces a value of NUMERIC(38,s).
+func (c *Context) Quantize128(d *Decimal128, x Decimal128, exp int32) (Condition, error) {
+	var dx, dd Decimal
+	dx.SetDecimal128(x)
+	res, err := c.Quantize(&dd, &dx, exp)
+	if err != nil {
+		return res, err
+	}
+	r, err := dd.Decimal128()
+	if err != nil {
+		return res, err
+	}
+	*d = r
+	return res, nil
+}
+
+// fallback128 computes op on x and y as Decimals and sets d to the result.
+func (c *Context) fallback128(
+	d *Decimal128, x, y Decimal128, op func(c *Context, d, x, y *Decimal) (Condition, error),
+) (Condition, error) {
+	var dx, dy, dd Decimal
+	dx.SetDecimal128(x)
+	dy.SetDecimal128(y)
+	res, err := op(c, &dd, &dx, &dy)
+	if err != nil {
+		return res, err
+	}
+	r, err := dd.Decimal128()
+	if err != nil {
+		return res, err
+	}
+	*d = r
+	return res, nil
+}
+
+// round128 rounds d according to c, mirroring Rounder.Round. It returns
+// false if d's coefficient has more than 38 digits after rounding or if its
+// exponent is outside of the normal range of c, in which case d is undefined
+// and the caller must fall back to Decimal.
+func (c *Context) round128(d *Decimal128) (Condition, bool) {
+	nd := d.coeff.numDigits()
+	exp := int64(d.exponent)
+	if !c.normal128(exp, nd) {
+		return 0, false
+	}
+	var res Condition
+	if diff := nd - int64(c.Precision); c.Precision != 0 && diff > 0 {
+		res |= Rounded
+		e := pow10Uint128[diff]
+		q, m := d.coeff.quoRem(e)
+		if !m.isZero() {
+			res |= Inexact
+			// m < e <= 10^38, so 2m can't overflow.
+			m, _ = m.add(m)
+			var tmp BigInt
+			if c.Rounding.ShouldAddOne(q.bigInt(&tmp), d.negative, m.cmp(e)) {
+				q = q.add64(1)
+				if q == pow10Uint128[int64(c.Precision)] {
+					q, _ = q.quoRem(pow10Uint128[1])
+					diff++
+				}
+			}
+		}
+		d.coeff = q
+		exp += diff
+		nd = int64(c.Precision)
+	}
+	if nd > MaxDecimal128Digits || !c.normal128(exp, nd) {
+		return 0, false
+	}
+	d.exponent = int32(exp)
+	return res, true
+}
+
+// normal128 returns whether a value with exponent exp and nd digits is
+// within the normal range of c and the package, where setExponent does not
+// need to do anything but set the exponent.
+func (c *Context) normal128(exp, nd int64) bool {
+	adj := exp + nd - 1
+	return exp <= MaxExponent && exp >= MinExponent &&
+		adj <= MaxExponent && adj >= MinExponent &&
+		adj >= int64(c.MinExponent) && adj <= int64(c.MaxExponent)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//                        unsigned 128-bit arithmetic                        //
+///////////////////////////////////////////////////////////////////////////////
+
+// uint128 is an unsigned 128-bit integer.
+type uint128 struct {
+	hi, lo uint64
+}
+
+// pow10Uint128 holds the powers of ten that fit in a uint128: 10^0 through
+// 10^38.
+var pow10Uint128 [MaxDecimal128Digits + 1]uint128
+
+func init() {
+	pow10Uint128[0] = uint128{lo: 1}
+	for i := 1; i < len(pow10Uint128); i++ {
+		pow10Uint128[i] = pow10Uint128[i-1].mul10()
+	}
+}
+
+func (u uint128) isZero() bool {
+	return u.hi == 0 && u.lo == 0
+}
+
+func (u uint128) cmp(v uint128) int {
+	switch {
+	case u.hi < v.hi:
+		return -1
+	case u.hi > v.hi:
+		return 1
+	case u.lo < v.lo:
+		return -1
+	case u.lo > v.lo:
+		return 1
+	}
+	return 0
+}
+
+// add returns u+v and whether the result did not overflow.
+func (u uint128) add(v uint128) (uint128, bool) {
+	lo, carry := bits.Add64(u.lo, v.lo, 0)
+	hi, carry := bits.Add64(u.hi, v.hi, carry)
+	return uint128{hi, lo}, carry == 0
+}
+
+// add64 returns u+v. It must not overflow.
+func (u uint128) add64(v uint64) uint128 {
+	lo, carry := bits.Add64(u.lo, v, 0)
+	return uint128{u.hi + carry, lo}
+}
+
+// sub returns u-v. v must not be greater than u.
+func (u uint128) sub(v uint128) uint128 {
+	lo, borrow := bits.Sub64(u.lo, v.lo, 0)
+	hi, _ := bits.Sub64(u.hi, v.hi, borrow)
+	return uint128{hi, lo}
+}
+
+// mul64 returns u*v and whether the result did not overflow.
+func (u uint128) mul64(v uint64) (uint128, bool) {
+	hi, lo := bits.Mul64(u.lo, v)
+	carry, mid := bits.Mul64(u.hi, v)
+	hi, c := bits.Add64(hi, mid, 0)
+	return uint128{hi, lo}, carry == 0 && c == 0
+}
+
+// mul returns u*v and whether the result did not overflow.
+func (u uint128) mul(v uint128) (uint128, bool) {
+	if u.hi != 0 && v.hi != 0 {
+		return uint128{}, false
+	}
+	if u.hi != 0 {
+		u, v = v, u
+	}
+	// u fits in 64 bits.
+	return v.mul64(u.lo)
+}
+
+// mul10 returns u*10. It must not overflow.
+func (u uint128) mul10() uint128 {
+	r, _ := u.mul64(10)
+	return r
+}
+
+// mulPow10 returns u*10^n and whether the result did not overflow.
+func (u uint128) mulPow10(n int64) (uint128, bool) {
+	if u.isZero() {
+		return u, true
+	}
+	if n >= int64(len(pow10Uint128)) {
+		return uint128{}, false
+	}
+	return u.mul(pow10Uint128[n])
+}
+
+// quoRem returns u/v and u%v. v must not be zero.
+func (u uint128) quoRem(v uint128) (q, r uint128) {
+	if v.hi == 0 {
+		var rhi uint64
+		q.hi, rhi = bits.Div64(0, u.hi, v.lo)
+		q.lo, r.lo = bits.Div64(rhi, u.lo, v.lo)
+		return q, r
+	}
+	// The quotient fits in 64 bits. Estimate it using the top 64 bits of the
+	// normalized divisor; the estimate is at most one too large.
+	n := uint(bits.LeadingZeros64(v.hi))
+	v1 := v.lsh(n)
+	u1 := u.rsh(1)
+	tq, _ := bits.Div64(u1.hi, u1.lo, v1.hi)
+	tq >>= 63 - n
+	if tq != 0 {
+		tq--
+	}
+	q = uint128{lo: tq}
+	p, _ := v.mul64(tq)
+	r = u.sub(p)
+	if r.cmp(v) >= 0 {
+		q = q.add64(1)
+		r = r.sub(v)
+	}
+	return q, r
+}
+
+// quoRem64 returns u/v and u%v. The quotient must fit in 64 bits.
+func (u uint128) quoRem64(v uint64) (q, r uint64) {
+	return bits.Div64(u.hi, u.lo, v)
+}
+
+func (u uint128) lsh(n uint) uint128 {
+	if n >= 64 {
+		return uint128{hi: u.lo << (n - 64)}
+	}
+	return uint128{hi: u.hi<<n | u.lo>>(64-n), lo: u.lo << n}
+}
+
+func (u uint128) rsh(n uint) uint128 {
+	if n >= 64 {
+		return uint128{lo: u.hi >> (n - 64)}
+	}
+	return uint128{hi: u.hi >> n, lo: u.lo>>n | u.hi<<(64-n)}
+}
+
+// numDigits returns the number of decimal digits in u. 0 has 1 digit.
+func (u uint128) numDigits() int64 {
+	// Estimate using the bit length, which can underestimate by one.
+	var bl int
+	if u.hi != 0 {
+		bl = 64 + bits.Len64(u.hi)
+	} else {
+		bl = bits.Len64(u.lo)
+	}
+	n := int64(bl) * 1233 >> 12 // 1233/4096 ≈ log10(2)
+	if n < int64(len(pow10Uint128)) && u.cmp(pow10Uint128[n]) >= 0 {
+		n++
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// bigInt sets b to u and returns b.
+func (u uint128) bigInt(b *BigInt) *BigInt {
+	if u.hi == 0 {
+		return b.SetUint64(u.lo)
+	}
+	for i := range b._inline {
+		b._inline[i] = big.Word(u.lo)
+		u = u.rsh(bits.UintSize)
+	}
+	b._inner = nil
+	return b
+}
+
+// uint128FromBigInt returns |b| as a uint128 and whether it fits in
+// MaxDecimal128Digits digits.
+func uint128FromBigInt(b *BigInt) (uint128, bool) {
+	if v, _, ok := b.innerAsUint64(); ok {
+		return uint128{lo: v}, true
+	}
+	if b.BitLen() > 128 {
+		return uint128{}, false
+	}
+	var u uint128
+	if b.isInline() {
+		for i := len(b._inline) - 1; i >= 0; i-- {
+			u = u.lsh(bits.UintSize)
+			u.lo |= uint64(b._inline[i])
+		}
+	} else {
+		var buf [16]byte
+		b.FillBytes(buf[:])
+		for i := 0; i < 8; i++ {
+			u.hi = u.hi<<8 | uint64(buf[i])
+			u.lo = u.lo<<8 | uint64(buf[i+8])
+		}
+	}
+	if u.cmp(pow10Uint128[MaxDecimal128Digits]) >= 0 {
+		return uint128{}, false
+	}
+	return u, true
+}
@@ -0,0 +1,267 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDecimal128Conversion(t *testing.T) {
+	tests := []struct {
+		s   string
+		err bool
+	}{
+		{s: "0"},
+		{s: "-0"},
+		{s: "0.00"},
+		{s: "1"},
+		{s: "-1.5"},
+		{s: "18446744073709551615"},
+		{s: "18446744073709551616"},
+		{s: "-1234567890123456789012.3456789E-10"},
+		{s: "99999999999999999999999999999999999999E+12"},
+		{s: "-99999999999999999999999999999999999999"},
+		{s: "100000000000000000000000000000000000000", err: true},
+		{s: "340282366920938463463374607431768211456", err: true},
+		{s: "NaN", err: true},
+		{s: "-Inf", err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d := newDecimal(t, testCtx, tc.s)
+			x, err := d.Decimal128()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %s", x)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var r Decimal
+			if r.SetDecimal128(x).CmpTotal(d) != 0 {
+				t.Fatalf("expected %s, got %s", d, &r)
+			}
+			if s := x.String(); s != d.String() {
+				t.Fatalf("expected %s, got %s", d, s)
+			}
+			if x.Sign() != d.Sign() || x.Negative() != d.Negative || x.Exponent() != d.Exponent {
+				t.Fatalf("unexpected sign or exponent: %s", x)
+			}
+		})
+	}
+
+	// Coefficients that were previously stored outside of the inline array.
+	var d Decimal
+	d.Coeff.SetString("123456789012345678901234567890123456789012345", 10)
+	d.Coeff.SetString("12345678901234567890123456789", 10)
+	if x, err := d.Decimal128(); err != nil {
+		t.Fatal(err)
+	} else if s := x.String(); s != "12345678901234567890123456789" {
+		t.Fatalf("unexpected: %s", s)
+	}
+}
+
+func TestDecimal128Cmp(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, dx := randDecimal128(rng)
+		y, dy := randDecimal128(rng)
+		if i%4 == 0 {
+			// Exercise equal values with different exponents.
+			y = x
+			y.coeff, _ = y.coeff.mulPow10(1)
+			y.exponent--
+			dy.SetDecimal128(y)
+			if y.coeff.numDigits() > MaxDecimal128Digits {
+				continue
+			}
+		}
+		if e, c := dx.Cmp(dy), x.Cmp(y); e != c {
+			t.Fatalf("%s cmp %s: expected %d, got %d", dx, dy, e, c)
+		}
+	}
+	if NewDecimal128(-0, 0).Cmp(NewDecimal128(0, 5)) != 0 {
+		t.Fatal("expected zeros to be equal")
+	}
+}
+
+// randDecimal128 returns a random Decimal128 and its Decimal equivalent.
+func randDecimal128(rng *rand.Rand) (Decimal128, *Decimal) {
+	var buf strings.Builder
+	if rng.Intn(2) == 0 {
+		buf.WriteByte('-')
+	}
+	n := 1 + rng.Intn(MaxDecimal128Digits)
+	if rng.Intn(4) == 0 {
+		n = 1 + rng.Intn(4)
+	}
+	for i := 0; i < n; i++ {
+		buf.WriteByte(byte('0' + rng.Intn(10)))
+	}
+	exp := rng.Intn(21) - 10
+	if rng.Intn(10) == 0 {
+		exp = rng.Intn(200) - 100
+	}
+	fmt.Fprintf(&buf, "E%d", exp)
+	d, _, err := NewFromString(buf.String())
+	if err != nil {
+		panic(err)
+	}
+	x, err := d.Decimal128()
+	if err != nil {
+		panic(err)
+	}
+	return x, d
+}
+
+// TestDecimal128Arithmetic verifies that the 128-bit operations produce the
+// same results as the Decimal operations.
+func TestDecimal128Arithmetic(t *testing.T) {
+	type op struct {
+		name  string
+		op    func(c *Context, d, x, y *Decimal) (Condition, error)
+		op128 func(c *Context, d *Decimal128, x, y Decimal128) (Condition, error)
+	}
+	ops := []op{
+		{"add", (*Context).Add, (*Context).Add128},
+		{"sub", (*Context).Sub, (*Context).Sub128},
+		{"mul", (*Context).Mul, (*Context).Mul128},
+		{"quo", (*Context).Quo, (*Context).Quo128},
+	}
+	var contexts []*Context
+	for _, p := range []uint32{0, 1, 5, 16, 34, 37, 38} {
+		for r := range roundings {
+			c := BaseContext.WithPrecision(p)
+			c.Rounding = r
+			c.Traps = 0
+			contexts = append(contexts, c)
+		}
+	}
+	narrow := BaseContext.WithPrecision(10)
+	narrow.MaxExponent = 20
+	narrow.MinExponent = -20
+	narrow.Traps = 0
+	contexts = append(contexts, narrow)
+
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 20000; i++ {
+		c := contexts[rng.Intn(len(contexts))]
+		o := ops[rng.Intn(len(ops))]
+		x, dx := randDecimal128(rng)
+		y, dy := randDecimal128(rng)
+
+		var want Decimal
+		wantRes, wantErr := o.op(c, &want, dx, dy)
+		if wantErr == nil {
+			if _, err := want.Decimal128(); err != nil {
+				wantErr = err
+			}
+		}
+		var got Decimal128
+		res, err := o.op128(c, &got, x, y)
+		desc := fmt.Sprintf("%s(%s, %s) P%d %s E[%d, %d]",
+			o.name, dx, dy, c.Precision, c.Rounding, c.MinExponent, c.MaxExponent)
+		if (err != nil) != (wantErr != nil) {
+			t.Fatalf("%s: expected error %v, got %v", desc, wantErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		var gotD Decimal
+		if gotD.SetDecimal128(got).CmpTotal(&want) != 0 || res != wantRes {
+			t.Fatalf("%s: expected %s (%s), got %s (%s)", desc, &want, wantRes, &gotD, res)
+		}
+	}
+}
+
+func TestDecimal128Allocs(t *testing.T) {
+	c := BaseContext.WithPrecision(MaxDecimal128Digits)
+	c.Rounding = RoundHalfEven
+	x := NewDecimal128(123456789, -4)
+	y := NewDecimal128(-98765, -2)
+	var big Decimal
+	big.SetString("1234567890123456789012345678.901234")
+	z, err := big.Decimal128()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Decimal128
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Add128(&d, x, y)
+		c.Sub128(&d, z, x)
+		c.Mul128(&d, x, y)
+		c.Mul128(&d, z, y)
+		c.Quo128(&d, x, y)
+		c.Quo128(&d, z, y)
+		x.Cmp(z)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
+
+func TestUint128QuoRem(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	rand128 := func() uint128 {
+		u := uint128{hi: rng.Uint64(), lo: rng.Uint64()}
+		return u.rsh(uint(rng.Intn(128)))
+	}
+	for i := 0; i < 10000; i++ {
+		u, v := rand128(), rand128()
+		if v.isZero() {
+			continue
+		}
+		q, r := u.quoRem(v)
+		var bu, bv, bq, br BigInt
+		u.bigInt(&bu)
+		v.bigInt(&bv)
+		bq.QuoRem(&bu, &bv, &br)
+		var eq, er BigInt
+		if q.bigInt(&eq).Cmp(&bq) != 0 || r.bigInt(&er).Cmp(&br) != 0 {
+			t.Fatalf("%s / %s: expected %s, %s; got %s, %s", &bu, &bv, &bq, &br, &eq, &er)
+		}
+	}
+}
+
+func TestQuoDigits64(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for i := 0; i < 10000; i++ {
+		v := rng.Uint64() >> uint(rng.Intn(64))
+		if v == 0 {
+			continue
+		}
+		// v <= u < 10v.
+		u, _ := uint128{lo: v}.mul64(uint64(rng.Intn(9) + 1))
+		u = u.add64(rng.Uint64() % v)
+		n := uint32(rng.Intn(MaxDecimal128Digits) + 1)
+		var bu, bv, bq, br BigInt
+		u.bigInt(&bu)
+		bu.Mul(&bu, tableExp10(int64(n-1), &bq))
+		bq.QuoRem(&bu, uint128{lo: v}.bigInt(&bv), &br)
+		if NumDigits(&bq) > MaxDecimal128Digits {
+			continue
+		}
+		q, r := quoDigits64(u, v, n)
+		var eq, er BigInt
+		if q.bigInt(&eq).Cmp(&bq) != 0 || r.bigInt(&er).Cmp(&br) != 0 {
+			t.Fatalf("%s / %d, %d digits: expected %s, %s; got %s, %s", &bu, v, n, &bq, &br, &eq, &er)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package apd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/bits"
@@ -128,7 +129,10 @@ func TestAdd(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(fmt.Sprintf("%s, %s", tc.x, tc.y), func(t *testing.T) {
 			x := newDecimal(t, testCtx, tc.x)
-			y := newDecimal(t, testCtx, tc.y)
+			y := new(Decimal)
+			if tc.y != "" {
+				y = newDecimal(t, testCtx, tc.y)
+			}
 			d := new(Decimal)
 			_, err := testCtx.Add(d, x, y)
 			if err != nil {
@@ -142,6 +146,253 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+// TestLargeExponents checks operations on operands with widely differing
+// exponents, which must not align them with a huge power of ten.
+func TestLargeExponents(t *testing.T) {
+	tests := []struct {
+		op   string
+		x, y string
+		r    string
+		res  Condition
+	}{
+		{op: "add", x: "1E+999999999", y: "1", r: "1.00000000E+999999999", res: Inexact | Rounded},
+		{op: "add", x: "1", y: "-1E+999999999", r: "-1.00000000E+999999999", res: Inexact | Rounded},
+		{op: "sub", x: "1E+999999999", y: "1", r: "1.00000000E+999999999", res: Inexact | Rounded},
+		{op: "add", x: "1", y: "1E-999999999", r: "1.00000000", res: Inexact | Rounded},
+		{op: "sub", x: "1", y: "1E-999999999", r: "1.00000000", res: Inexact | Rounded},
+		{op: "add", x: "1", y: "0E-999999999", r: "1.00000000", res: Rounded},
+		{op: "add", x: "0E+999999999", y: "1E-999999999", r: "1E-999999999"},
+		{op: "add", x: "-0E+999999999", y: "0E-999999999", r: "0E-999999999"},
+		{op: "mul", x: "1E+999999999", y: "1E+999999999", r: "Infinity", res: Overflow | Inexact | Rounded},
+		{op: "mul", x: "1E-999999999", y: "1E-999999999", r: "0E-1000000007", res: Underflow | Subnormal | Inexact | Rounded | Clamped},
+		{op: "quo", x: "1E+999999999", y: "1E-999999999", r: "Infinity", res: Overflow | Inexact | Rounded},
+		{op: "quo", x: "1", y: "3E+999999999", r: "3.3333333E-1000000000", res: Underflow | Subnormal | Inexact | Rounded},
+		{op: "quointeger", x: "1", y: "3E+999999999", r: "0"},
+		{op: "quointeger", x: "1E+999999999", y: "3", r: "NaN", res: DivisionImpossible},
+		{op: "rem", x: "1", y: "3E+999999999", r: "1"},
+		{op: "rem", x: "0E+999999999", y: "3E-999999999", r: "0E-999999999"},
+		{op: "rem", x: "1E+999999999", y: "3", r: "NaN", res: DivisionImpossible},
+		{op: "quantize", x: "0E+999999999", y: "1E-999999999", r: "0E-999999999"},
+		{op: "quantize", x: "1E+999999999", y: "1E-999999999", r: "NaN", res: InvalidOperation},
+		{op: "cbrt", x: "1E+999999999", r: "1.00000000E+333333333"},
+		{op: "cbrt", x: "-8E-999999999", r: "-2.00000000E-333333333"},
+		{op: "cbrt", x: "1E+999999998", r: "4.64158883E+333333332", res: Inexact | Rounded},
+		{op: "cbrt", x: "1E-999999997", r: "4.64158883E-333333333", res: Inexact | Rounded},
+	}
+	c := testCtx.WithPrecision(9)
+	c.Traps = 0
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%s %s, %s", tc.op, tc.x, tc.y), func(t *testing.T) {
+			x := newDecimal(t, testCtx, tc.x)
+			y := new(Decimal)
+			if tc.y != "" {
+				y = newDecimal(t, testCtx, tc.y)
+			}
+			d := new(Decimal)
+			var res Condition
+			var err error
+			switch tc.op {
+			case "add":
+				res, err = c.Add(d, x, y)
+			case "sub":
+				res, err = c.Sub(d, x, y)
+			case "mul":
+				res, err = c.Mul(d, x, y)
+			case "quo":
+				res, err = c.Quo(d, x, y)
+			case "quointeger":
+				res, err = c.QuoInteger(d, x, y)
+			case "rem":
+				res, err = c.Rem(d, x, y)
+			case "quantize":
+				res, err = c.Quantize(d, x, y.Exponent)
+			case "cbrt":
+				res, err = c.Cbrt(d, x)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := d.String(); s != tc.r {
+				t.Errorf("expected %s, got %s", tc.r, s)
+			}
+			if res != tc.res {
+				t.Errorf("expected %s, got %s", tc.res, res)
+			}
+		})
+	}
+
+	// A negligible operand still decides the direction of rounding.
+	down := c.WithPrecision(9)
+	down.Rounding = RoundDown
+	d := new(Decimal)
+	if _, err := down.Sub(d, decimalOne, newDecimal(t, testCtx, "1E-999999999")); err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "0.999999999" {
+		t.Fatalf("expected 0.999999999, got %s", s)
+	}
+
+	// Exact operations must still align their operands.
+	x := newDecimal(t, testCtx, "1E+999999999")
+	if _, err := testCtx.Add(new(Decimal), x, decimalOne); err == nil || !strings.Contains(err.Error(), errExponentOutOfRangeStr) {
+		t.Fatalf("expected exponent error, got %v", err)
+	}
+	if x.Cmp(newDecimal(t, testCtx, "9E+999999998")) != 1 {
+		t.Fatal("expected 1E+999999999 > 9E+999999998")
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		xs   []string
+		prec uint32
+		r    string
+		res  Condition
+	}{
+		{xs: nil, r: "0"},
+		{xs: []string{"1", "10", "1e2"}, r: "111"},
+		{xs: []string{"1", "0.00"}, r: "1.00"},
+		{xs: []string{"-0", "-0.0"}, r: "-0.0"},
+		{xs: []string{"-0", "0"}, r: "0"},
+		{xs: []string{"1.5", "-1.5"}, r: "0.0"},
+		// Repeated Add would round 1e4 + 1 to 1.00E+4 at this precision.
+		{xs: []string{"1e4", "1", "-1e4"}, prec: 3, r: "1"},
+		{xs: []string{"1", "1e4", "-1e4"}, prec: 3, r: "1"},
+		{xs: []string{"0.333", "0.333", "0.335"}, prec: 2, r: "1.0", res: Inexact | Rounded},
+		{xs: []string{"1", "Inf", "2"}, r: "Infinity"},
+		{xs: []string{"-Inf", "-Inf"}, r: "-Infinity"},
+		{xs: []string{"Inf", "-Inf"}, r: "NaN", res: InvalidOperation},
+		{xs: []string{"1", "NaN", "sNaN"}, r: "NaN", res: InvalidOperation},
+		{xs: []string{"1", "NaN", "Inf"}, r: "NaN"},
+		// Terms too far apart to align exactly are rounded as Add does.
+		{xs: []string{"1E+999999999", "1"}, prec: 20, r: "1.0000000000000000000E+999999999", res: Inexact | Rounded},
+		{xs: []string{"1E+999999999", "-1"}, prec: 3, r: "1.00E+999999999", res: Inexact | Rounded},
+		{xs: []string{"1E+999999999", "1", "-1E+999999999", "1E-999999999"}, prec: 5, r: "1.0000", res: Inexact | Rounded},
+		{xs: []string{"1E-999999999", "-1E+999999999", "1E+999999999"}, prec: 5, r: "1E-999999999"},
+		{xs: []string{"0E+999999999", "5", "-0E-999999999"}, prec: 5, r: "5.0000", res: Rounded},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.xs, ", "), func(t *testing.T) {
+			c := testCtx.WithPrecision(tc.prec)
+			c.Traps = 0
+			xs := make([]*Decimal, len(tc.xs))
+			for i, s := range tc.xs {
+				xs[i] = newDecimal(t, testCtx, s)
+			}
+			d := new(Decimal)
+			res, err := c.Sum(d, xs...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := d.String(); s != tc.r {
+				t.Fatalf("expected: %s, got: %s", tc.r, s)
+			}
+			if res != tc.res {
+				t.Fatalf("expected condition %s, got %s", tc.res, res)
+			}
+		})
+	}
+}
+
+func TestSumOrderIndependent(t *testing.T) {
+	c := BaseContext.WithPrecision(5)
+	xs := []*Decimal{New(99999, 0), New(4, -1), New(-99999, 0), New(3, -1), New(1, -4)}
+	var want Decimal
+	if _, err := c.Sum(&want, xs...); err != nil {
+		t.Fatal(err)
+	}
+	if want.String() != "0.7001" {
+		t.Fatalf("expected 0.7001, got %s", &want)
+	}
+	for i := range xs {
+		// Rotate the operands and verify the result does not change.
+		xs = append(xs[1:], xs[0])
+		var d Decimal
+		if _, err := c.Sum(&d, xs...); err != nil {
+			t.Fatal(err)
+		}
+		if d.CmpTotal(&want) != 0 {
+			t.Fatalf("%d: expected %s, got %s", i, &want, &d)
+		}
+	}
+}
+
+// TestSumMatchesAdd checks that the sum of two terms whose exponents are too
+// far apart to align exactly is rounded as Add rounds it.
+func TestSumMatchesAdd(t *testing.T) {
+	values := []string{"1E+999999999", "-9.87E+999999990", "1", "-0.5", "5E+200000", "-0E+300000", "0E-999999999", "-1E-999999999", "123456789E-500000"}
+	for _, prec := range []uint32{1, 5, 20} {
+		for _, rounding := range []Rounder{RoundHalfEven, RoundDown, RoundUp, RoundFloor, RoundCeiling} {
+			c := testCtx.WithPrecision(prec)
+			c.Traps = 0
+			c.Rounding = rounding
+			for _, xs := range values {
+				for _, ys := range values {
+					x, y := newDecimal(t, testCtx, xs), newDecimal(t, testCtx, ys)
+					var want, got Decimal
+					wantRes, err := c.Add(&want, x, y)
+					if err != nil {
+						t.Fatal(err)
+					}
+					res, err := c.Sum(&got, x, y)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got.CmpTotal(&want) != 0 || res != wantRes {
+						t.Fatalf("%d %s: %s + %s: expected %s (%s), got %s (%s)", prec, rounding, xs, ys, &want, wantRes, &got, res)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDotProduct(t *testing.T) {
+	tests := []struct {
+		xs, ys []string
+		prec   uint32
+		r      string
+		res    Condition
+	}{
+		{xs: nil, ys: nil, r: "0"},
+		{xs: []string{"1.5", "2"}, ys: []string{"2", "0.25"}, r: "3.50"},
+		{xs: []string{"1e3", "1", "-1e3"}, ys: []string{"1e3", "1", "1e3"}, prec: 2, r: "1"},
+		{xs: []string{"3"}, ys: []string{"3.33"}, prec: 2, r: "10", res: Inexact | Rounded},
+		{xs: []string{"-Inf", "1"}, ys: []string{"-2", "3"}, r: "Infinity"},
+		{xs: []string{"Inf", "1"}, ys: []string{"0", "3"}, r: "NaN", res: InvalidOperation},
+		{xs: []string{"Inf", "Inf"}, ys: []string{"1", "-1"}, r: "NaN", res: InvalidOperation},
+		{xs: []string{"1", "2"}, ys: []string{"NaN", "sNaN"}, r: "NaN", res: InvalidOperation},
+		{xs: []string{"1E+500000000", "-3"}, ys: []string{"1E+499999999", "1E-999999999"}, prec: 20, r: "1.0000000000000000000E+999999999", res: Inexact | Rounded},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.xs, ", ")+"; "+strings.Join(tc.ys, ", "), func(t *testing.T) {
+			c := testCtx.WithPrecision(tc.prec)
+			c.Traps = 0
+			xs := make([]*Decimal, len(tc.xs))
+			ys := make([]*Decimal, len(tc.ys))
+			for i := range tc.xs {
+				xs[i] = newDecimal(t, testCtx, tc.xs[i])
+				ys[i] = newDecimal(t, testCtx, tc.ys[i])
+			}
+			d := new(Decimal)
+			res, err := c.DotProduct(d, xs, ys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := d.String(); s != tc.r {
+				t.Fatalf("expected: %s, got: %s", tc.r, s)
+			}
+			if res != tc.res {
+				t.Fatalf("expected condition %s, got %s", tc.res, res)
+			}
+		})
+	}
+
+	if _, err := testCtx.DotProduct(new(Decimal), []*Decimal{New(1, 0)}, nil); err == nil {
+		t.Fatal("expected error for mismatched lengths")
+	}
+}
+
 func TestCmp(t *testing.T) {
 	tests := []struct {
 		x, y string
@@ -626,6 +877,142 @@ func TestContextSetStringt(t *testing.T) {
 	}
 }
 
+func TestSetStringBytes(t *testing.T) {
+	tests := []string{
+		"0", "-0", "+1", "1.5", "-.5", "5.", "1E+3", "1e-3", "-1.25E-7",
+		"00012345678901234567890.123", "123456789012345678901234567890",
+		"9999999999999999999", "18446744073709551615", "1.8446744073709551616E+19",
+		"inf", "-Infinity", "INF", "NaN", "-nan123", "sNaN", "SNAN18446744073709551615",
+		"1e100000", "1e-100000",
+	}
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			expected, eres, err := NewFromString(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := []byte(s)
+			var d Decimal
+			if _, res, err := d.SetStringBytes(b); err != nil {
+				t.Fatal(err)
+			} else if res != eres {
+				t.Fatalf("expected %s, got %s", eres, res)
+			}
+			if d.Form != expected.Form || d.Negative != expected.Negative ||
+				d.String() != expected.String() {
+				t.Fatalf("expected %s, got %s", expected, &d)
+			}
+			// d must not alias b.
+			for i := range b {
+				b[i] = 'x'
+			}
+			if d.String() != expected.String() {
+				t.Fatalf("expected %s, got %s", expected, &d)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		s      string
+		offset int
+		reason string
+	}{
+		{s: "", offset: 0, reason: "missing digits"},
+		{s: "-", offset: 1, reason: "missing digits"},
+		{s: ".", offset: 1, reason: "missing digits"},
+		{s: "-+1", offset: 1, reason: "missing digits"},
+		{s: "abc", offset: 0, reason: "missing digits"},
+		{s: "1.2.3", offset: 3, reason: `unexpected character '.'`},
+		{s: "12x", offset: 2, reason: `unexpected character 'x'`},
+		{s: "1€", offset: 1, reason: `unexpected character '€'`},
+		{s: "1 ", offset: 1, reason: `unexpected character ' '`},
+		{s: "1e", offset: 2, reason: "missing exponent digits"},
+		{s: "1e+", offset: 3, reason: "missing exponent digits"},
+		{s: "1e5x", offset: 3, reason: `unexpected character 'x'`},
+		{s: "1e2147483648", offset: 2, reason: errExponentOutOfRangeStr},
+		{s: "1e-2147483649", offset: 3, reason: errExponentOutOfRangeStr},
+		{s: "infinite", offset: 0, reason: "missing digits"},
+		{s: "NaNx", offset: 3, reason: "invalid NaN payload"},
+		{s: "-sNaN1-", offset: 6, reason: "invalid NaN payload"},
+		{s: "nan18446744073709551616", offset: 22, reason: "NaN payload value out of range"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			_, _, err := new(Decimal).SetStringBytes([]byte(tc.s))
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected ParseError, got %v", err)
+			}
+			if pe.Input != tc.s || pe.Offset != tc.offset || pe.Reason != tc.reason {
+				t.Fatalf("expected %q at %d: %s, got %q at %d: %s",
+					tc.s, tc.offset, tc.reason, pe.Input, pe.Offset, pe.Reason)
+			}
+			// SetString returns the same error.
+			if _, _, err := NewFromString(tc.s); err == nil || err.Error() != pe.Error() {
+				t.Fatalf("expected %v, got %v", pe, err)
+			}
+		})
+	}
+}
+
+func TestSetStringBytesAllocs(t *testing.T) {
+	var d Decimal
+	for _, s := range []string{"-1234567.890123456789", "1E+5", "NaN", "-Infinity"} {
+		b := []byte(s)
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, _, err := d.SetStringBytes(b); err != nil {
+				t.Fatal(err)
+			}
+			if err := d.UnmarshalText(b); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %.1f", s, allocs)
+		}
+	}
+}
+
+func TestSmallOperandsAllocs(t *testing.T) {
+	c := BaseContext.WithPrecision(34)
+	for _, ops := range [][2]string{
+		{"1234.56", "78.9"},
+		{"-0.01", "19.99"},
+		{"99999999999999999.99", "1E+5"},
+		{"12345678901234567890", "-1E-12"},
+		{"10.00", "3"},
+	} {
+		x, y := newDecimal(t, testCtx, ops[0]), newDecimal(t, testCtx, ops[1])
+		var d Decimal
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, err := c.Add(&d, x, y); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Sub(&d, x, y); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Mul(&d, x, y); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Quo(&d, x, y); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.QuoInteger(&d, x, y); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Rem(&d, x, y); err != nil {
+				t.Fatal(err)
+			}
+			_ = x.Cmp(y)
+		})
+		if allocs != 0 {
+			t.Errorf("%s, %s: expected no allocations, got %.1f", ops[0], ops[1], allocs)
+		}
+	}
+}
+
 func TestQuantize(t *testing.T) {
 	tests := []struct {
 		s      string
@@ -812,12 +1199,12 @@ func TestSizeof(t *testing.T) {
 		32: {
 			"BigInt":  20,
 			"Decimal": 28,
-			"Context": 24,
+			"Context": 28,
 		},
 		64: {
 			"BigInt":  24,
 			"Decimal": 32,
-			"Context": 32,
+			"Context": 40,
 		},
 	}[bits.UintSize]
 
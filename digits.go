@@ -0,0 +1,106 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"math/big"
+	"sync"
+)
+
+// Conversions of decimal strings of many digits to coefficients split the
+// digits in halves at a power of ten, so they cost a few multiplications of
+// the size of the coefficient instead of one multiplication by a word per
+// digit.
+
+const (
+	// digitsLeafSize is the number of digits below which coefficients are
+	// converted by math/big directly.
+	digitsLeafSize = 512
+	// setDigitsThreshold is the number of digits above which setDigits
+	// splits the conversion.
+	setDigitsThreshold = 4 * digitsLeafSize
+	// digitsCacheLevels is the number of powers of ten that are cached, the
+	// largest of which is 10^(digitsLeafSize<<(digitsCacheLevels-1)). Larger
+	// powers are computed for each conversion, so that converting a huge
+	// coefficient does not keep its powers of ten in memory.
+	digitsCacheLevels = 6
+)
+
+// digitsPow10 caches 10^(digitsLeafSize<<i) in pows[i].
+var digitsPow10 struct {
+	sync.Mutex
+	pows []*big.Int
+}
+
+// digitsPowers returns the powers of ten 10^(digitsLeafSize<<i) with fewer
+// than n digits. n must be greater than digitsLeafSize. The returned values
+// must not be mutated.
+func digitsPowers(n int) []*big.Int {
+	levels := 1
+	for k := digitsLeafSize; 2*k < n; k *= 2 {
+		levels++
+	}
+	pows := make([]*big.Int, levels)
+	digitsPow10.Lock()
+	for len(digitsPow10.pows) < levels && len(digitsPow10.pows) < digitsCacheLevels {
+		p := new(big.Int)
+		if j := len(digitsPow10.pows); j == 0 {
+			p.Exp(bigIntTen, big.NewInt(digitsLeafSize), nil)
+		} else {
+			p.Mul(digitsPow10.pows[j-1], digitsPow10.pows[j-1])
+		}
+		digitsPow10.pows = append(digitsPow10.pows, p)
+	}
+	cached := copy(pows, digitsPow10.pows)
+	digitsPow10.Unlock()
+	for i := cached; i < levels; i++ {
+		pows[i] = new(big.Int).Mul(pows[i-1], pows[i-1])
+	}
+	return pows
+}
+
+// setDigits sets z to the value of the decimal digits s and returns z. s must
+// consist only of the digits '0' through '9'.
+func (z *BigInt) setDigits(s string) *BigInt {
+	if len(s) <= setDigitsThreshold {
+		z.SetString(s, 10)
+		return z
+	}
+	var tmp big.Int //gcassert:noescape
+	zi := z.inner(&tmp)
+	zi.Set(setDigits(s, digitsPowers(len(s))))
+	z.updateInner(zi)
+	return z
+}
+
+// setDigits returns the value of the decimal digits s as a new big.Int. pows
+// holds the powers of ten returned by digitsPowers for at least len(s).
+func setDigits(s string, pows []*big.Int) *big.Int {
+	if len(s) <= digitsLeafSize {
+		z, _ := new(big.Int).SetString(s, 10)
+		return z
+	}
+	// s is hi×10^k + lo, where lo is the last k digits and k is the largest
+	// of the powers with fewer than len(s) digits, so hi has at most k
+	// digits.
+	i := len(pows) - 1
+	for digitsLeafSize<<i >= len(s) {
+		i--
+	}
+	k := digitsLeafSize << i
+	z := setDigits(s[:len(s)-k], pows[:i])
+	z.Mul(z, pows[i])
+	return z.Add(z, setDigits(s[len(s)-k:], pows[:i]))
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randDigits returns n random decimal digits. The first digit is not zero.
+func randDigits(rng *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + rng.Intn(10))
+	}
+	if n > 0 && b[0] == '0' {
+		b[0] = '1'
+	}
+	return string(b)
+}
+
+func TestDigits(t *testing.T) {
+	rng := rand.New(rand.NewSource(20261018))
+	var tests []string
+	for _, n := range []int{
+		1, 19, 20, digitsLeafSize - 1, digitsLeafSize, digitsLeafSize + 1,
+		setDigitsThreshold, setDigitsThreshold + 1, 3 * setDigitsThreshold,
+		16 * digitsLeafSize, 40000,
+	} {
+		tests = append(tests, randDigits(rng, n))
+		// Runs of zeros and nines exercise the padding of the low halves and
+		// quotients with fewer digits than estimated.
+		tests = append(tests, "1"+strings.Repeat("0", n))
+		tests = append(tests, strings.Repeat("9", n))
+		tests = append(tests, "5"+strings.Repeat("0", n/2)+"7"+strings.Repeat("0", n/2))
+	}
+	for _, s := range tests {
+		var z BigInt
+		z.setDigits(s)
+		want, _ := new(big.Int).SetString(s, 10)
+		if got := z.MathBigInt(); got.Cmp(want) != 0 {
+			t.Fatalf("setDigits(%d digits): got %s", len(s), got)
+		}
+		// Round trip through Decimal.
+		d, _, err := NewFromString(s[:len(s)/2] + "." + s[len(s)/2:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Coeff.MathBigInt().Cmp(want) != 0 {
+			t.Fatalf("NewFromString(%d digits): wrong coefficient", len(s))
+		}
+		if got := d.Text('f'); strings.Replace(got, ".", "", 1) != strings.TrimLeft(s, "0") && got != "0."+s {
+			t.Fatalf("Text(%d digits): got %s", len(s), got)
+		}
+	}
+	// The 40000-digit conversions above need more powers of ten than are
+	// cached.
+	digitsPow10.Lock()
+	defer digitsPow10.Unlock()
+	if n := len(digitsPow10.pows); n > digitsCacheLevels {
+		t.Fatalf("cached %d powers of ten, want at most %d", n, digitsCacheLevels)
+	}
+}
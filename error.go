@@ -14,6 +14,8 @@
 
 package apd
 
+import "fmt"
+
 // MakeErrDecimal creates a ErrDecimal with given context.
 func MakeErrDecimal(c *Context) ErrDecimal {
 	return ErrDecimal{
@@ -77,6 +79,15 @@ func (e *ErrDecimal) Ceil(d, x *Decimal) *Decimal {
 	return d
 }
 
+// DotProduct performs e.Ctx.DotProduct(d, xs, ys) and returns d.
+func (e *ErrDecimal) DotProduct(d *Decimal, xs, ys []*Decimal) *Decimal {
+	if e.Err() != nil {
+		return d
+	}
+	e.update(e.Ctx.DotProduct(d, xs, ys))
+	return d
+}
+
 // Exp performs e.Ctx.Exp(d, x) and returns d.
 func (e *ErrDecimal) Exp(d, x *Decimal) *Decimal {
 	if e.Err() != nil {
@@ -224,6 +235,15 @@ func (e *ErrDecimal) Sub(d, x, y *Decimal) *Decimal {
 	return d
 }
 
+// Sum performs e.Ctx.Sum(d, xs...) and returns d.
+func (e *ErrDecimal) Sum(d *Decimal, xs ...*Decimal) *Decimal {
+	if e.Err() != nil {
+		return d
+	}
+	e.update(e.Ctx.Sum(d, xs...))
+	return d
+}
+
 // RoundToIntegralValue performs e.Ctx.RoundToIntegralValue(d, x) and returns d.
 func (e *ErrDecimal) RoundToIntegralValue(d, x *Decimal) *Decimal {
 	if e.Err() != nil {
@@ -241,3 +261,50 @@ func (e *ErrDecimal) RoundToIntegralExact(d, x *Decimal) *Decimal {
 	e.update(e.Ctx.RoundToIntegralExact(d, x))
 	return d
 }
+
+// ParseError is returned when text cannot be parsed as a Decimal.
+type ParseError struct {
+	// Input is the text being parsed.
+	Input string
+	// Offset is the byte offset in Input at which parsing failed.
+	Offset int
+	// Reason describes why parsing failed.
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("could not parse %q: %s at offset %d", e.Input, e.Reason, e.Offset)
+}
+
+// newParseError returns a ParseError with a copy of s, which may alias a byte
+// slice owned by the caller.
+func newParseError(s string, offset int, reason string) error {
+	return &ParseError{Input: cloneString(s), Offset: offset, Reason: reason}
+}
+
+// ElementError is an error that occurred while processing the element at
+// Index of a slice.
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+func (e ElementError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ElementError) Unwrap() error {
+	return e.Err
+}
+
+// ElementErrors is the list of errors, in order of index, of the elements of
+// a slice that could not be processed by a bulk operation.
+type ElementErrors []ElementError
+
+func (e ElementErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0].Error(), len(e)-1)
+}
@@ -137,3 +137,33 @@ func ExampleContext_RoundToIntegralExact() {
 	// input: 120E-1, output:  12, integer:  true, strict: false, res: rounded
 	// input: 120E-2, output:   1, integer: false, strict: false, res: inexact, rounded
 }
+
+func ExampleValue() {
+	c := apd.BaseContext.WithPrecision(5)
+	price := apd.NewValue(1999, -2).WithContext(c)
+	qty := apd.NewValue(3, 0)
+	total := price.Mul(qty).Quo(apd.NewValue(7, 0))
+	fmt.Printf("%s, flags: %s, err: %v\n", total, total.Flags(), total.Err())
+	bad := total.Quo(apd.NewValue(0, 0)).Add(qty)
+	fmt.Printf("%s, err: %v\n", bad, bad.Err())
+	// Output:
+	// 8.5671, flags: inexact, rounded, err: <nil>
+	// Infinity, err: division by zero
+}
+
+// ExampleContext_SetStringOptions demonstrates parsing with strict and lenient
+// grammars.
+func ExampleContext_SetStringOptions() {
+	var d apd.Decimal
+	for _, s := range []string{"1.5", "+1.5", " 1_000.5 ", "12.5%", "−2"} {
+		_, _, jsonErr := apd.BaseContext.SetStringOptions(&d, s, apd.ParseJSON)
+		_, _, err := apd.BaseContext.SetStringOptions(&d, s, apd.ParseLenient)
+		fmt.Printf("%-11q JSON: %5v, lenient: %s, %v\n", s, jsonErr == nil, &d, err)
+	}
+	// Output:
+	// "1.5"       JSON:  true, lenient: 1.5, <nil>
+	// "+1.5"      JSON: false, lenient: 1.5, <nil>
+	// " 1_000.5 " JSON: false, lenient: 1000.5, <nil>
+	// "12.5%"     JSON: false, lenient: 0.125, <nil>
+	// "−2"        JSON: false, lenient: -2, <nil>
+}
@@ -0,0 +1,445 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// MaxFixedScale is the largest Scale of a Fixed.
+const MaxFixedScale = 18
+
+// Fixed is a fixed-point decimal stored in an int64. Its value is:
+//
+//	Units × 10**-Scale
+//
+// For example, a price of 12.34 with a scale of 4 is Fixed{Units: 123400,
+// Scale: 4}. Scale must be at most MaxFixedScale: Add, Sub, Mul, Quo and
+// Rescale of a Fixed with a larger Scale produce the InvalidOperation
+// condition and an error.
+//
+// Arithmetic on Fixed does not allocate and is checked: instead of wrapping,
+// results that don't fit in an int64 produce the Overflow condition and an
+// error, as does division by zero. The result of an operation has the larger
+// of the scales of its operands. Mul and Quo round their result to that scale
+// using a Rounder and report the Inexact and Rounded conditions when they do.
+type Fixed struct {
+	Units int64
+	Scale uint8
+}
+
+// NewFixed returns a Fixed with the given units and scale. An error is
+// returned if scale is greater than MaxFixedScale.
+func NewFixed(units int64, scale uint8) (Fixed, error) {
+	if scale > MaxFixedScale {
+		return Fixed{}, fmt.Errorf("scale %d greater than %d", scale, MaxFixedScale)
+	}
+	return Fixed{Units: units, Scale: scale}, nil
+}
+
+// fixedError returns the error for res, which is an error if it contains any
+// of the default traps.
+func fixedError(res Condition) (Fixed, Condition, error) {
+	_, err := res.GoError(DefaultTraps)
+	return Fixed{}, res, err
+}
+
+// Sign returns -1 if x < 0, 0 if x == 0, and +1 if x > 0.
+func (x Fixed) Sign() int {
+	switch {
+	case x.Units < 0:
+		return -1
+	case x.Units > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero returns true if x == 0.
+func (x Fixed) IsZero() bool {
+	return x.Units == 0
+}
+
+// Cmp compares x and y and returns:
+//
+//	-1 if x <  y
+//	 0 if x == y
+//	+1 if x >  y
+func (x Fixed) Cmp(y Fixed) int {
+	xs, ys := x.Sign(), y.Sign()
+	if xs != ys {
+		if xs < ys {
+			return -1
+		}
+		return 1
+	}
+	a := uint128{lo: absInt64(x.Units)}
+	b := uint128{lo: absInt64(y.Units)}
+	// For valid scales the aligned magnitudes fit in 128 bits. Otherwise an
+	// aligned magnitude that doesn't fit is the larger one.
+	var ok bool
+	if x.Scale < y.Scale {
+		if a, ok = a.mulPow10(int64(y.Scale - x.Scale)); !ok {
+			a = uint128{hi: math.MaxUint64, lo: math.MaxUint64}
+		}
+	} else if x.Scale > y.Scale {
+		if b, ok = b.mulPow10(int64(x.Scale - y.Scale)); !ok {
+			b = uint128{hi: math.MaxUint64, lo: math.MaxUint64}
+		}
+	}
+	c := a.cmp(b)
+	if xs < 0 {
+		c = -c
+	}
+	return c
+}
+
+// Neg returns -x.
+func (x Fixed) Neg() (Fixed, Condition, error) {
+	if x.Units == math.MinInt64 {
+		return fixedError(Overflow)
+	}
+	return Fixed{Units: -x.Units, Scale: x.Scale}, 0, nil
+}
+
+// Abs returns |x|.
+func (x Fixed) Abs() (Fixed, Condition, error) {
+	if x.Units < 0 {
+		return x.Neg()
+	}
+	return x, 0, nil
+}
+
+// Add returns x+y.
+func (x Fixed) Add(y Fixed) (Fixed, Condition, error) {
+	if !validFixedScales(x, y) {
+		return fixedError(InvalidOperation)
+	}
+	a, b, scale, ok := alignFixed(x, y)
+	if !ok {
+		return fixedError(Overflow)
+	}
+	sum := a + b
+	// Overflow occurred if both operands have the same sign and the sign of
+	// the sum differs.
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return fixedError(Overflow)
+	}
+	return Fixed{Units: sum, Scale: scale}, 0, nil
+}
+
+// Sub returns x-y.
+func (x Fixed) Sub(y Fixed) (Fixed, Condition, error) {
+	if !validFixedScales(x, y) {
+		return fixedError(InvalidOperation)
+	}
+	a, b, scale, ok := alignFixed(x, y)
+	if !ok {
+		return fixedError(Overflow)
+	}
+	diff := a - b
+	// Overflow occurred if the operands have different signs and the sign of
+	// the difference differs from a.
+	if (a >= 0) != (b >= 0) && (diff >= 0) != (a >= 0) {
+		return fixedError(Overflow)
+	}
+	return Fixed{Units: diff, Scale: scale}, 0, nil
+}
+
+// Mul returns x*y, rounded with r to the larger scale of x and y.
+func (x Fixed) Mul(y Fixed, r Rounder) (Fixed, Condition, error) {
+	if !validFixedScales(x, y) {
+		return fixedError(InvalidOperation)
+	}
+	scale := maxScale(x, y)
+	p, _ := uint128{lo: absInt64(x.Units)}.mul64(absInt64(y.Units))
+	shift := int(x.Scale) + int(y.Scale) - int(scale)
+	var res Condition
+	if shift > 0 {
+		res |= Rounded
+	}
+	d := pow10Uint128[shift].lo
+	q, m := p.quoRem(uint128{lo: d})
+	return roundFixed(q, m, d, (x.Units < 0) != (y.Units < 0), scale, r, res)
+}
+
+// Quo returns x/y, rounded with r to the larger scale of x and y.
+func (x Fixed) Quo(y Fixed, r Rounder) (Fixed, Condition, error) {
+	if !validFixedScales(x, y) {
+		return fixedError(InvalidOperation)
+	}
+	if y.Units == 0 {
+		if x.Units == 0 {
+			return fixedError(DivisionUndefined)
+		}
+		return fixedError(DivisionByZero)
+	}
+	scale := maxScale(x, y)
+	d := absInt64(y.Units)
+	// x/y at the result scale is x.Units*10^shift/y.Units. The shift can be up
+	// to 36, so divide in two steps to keep the dividends within 128 bits.
+	shift := int64(scale) - int64(x.Scale) + int64(y.Scale)
+	first := shift
+	if first > 19 {
+		first = 19
+	}
+	n, _ := uint128{lo: absInt64(x.Units)}.mulPow10(first)
+	q, m := n.quoRem(uint128{lo: d})
+	if rest := shift - first; rest > 0 {
+		// m < d < 2^64, so m*10^rest fits in 128 bits.
+		n, _ = m.mulPow10(rest)
+		var q2 uint128
+		q2, m = n.quoRem(uint128{lo: d})
+		var ok bool
+		if q, ok = q.mulPow10(rest); ok {
+			q, ok = q.add(q2)
+		}
+		if !ok {
+			return fixedError(Overflow)
+		}
+	}
+	return roundFixed(q, m, d, (x.Units < 0) != (y.Units < 0), scale, r, 0)
+}
+
+// Rescale returns x with the given scale, rounded with r if the scale is
+// reduced.
+func (x Fixed) Rescale(scale uint8, r Rounder) (Fixed, Condition, error) {
+	if scale > MaxFixedScale || x.Scale > MaxFixedScale {
+		return fixedError(InvalidOperation)
+	}
+	if scale >= x.Scale {
+		a, ok := scaleInt64(x.Units, scale-x.Scale)
+		if !ok {
+			return fixedError(Overflow)
+		}
+		return Fixed{Units: a, Scale: scale}, 0, nil
+	}
+	d := pow10Uint128[x.Scale-scale].lo
+	q, m := uint128{lo: absInt64(x.Units)}.quoRem(uint128{lo: d})
+	return roundFixed(q, m, d, x.Units < 0, scale, r, Rounded)
+}
+
+// roundFixed returns the Fixed with the given scale and sign whose units are
+// the quotient q with remainder m of a division by d, rounded with r. res is
+// any Condition previously set for this operation.
+func roundFixed(q, m uint128, d uint64, neg bool, scale uint8, r Rounder, res Condition) (Fixed, Condition, error) {
+	if q.hi != 0 {
+		return fixedError(res | Overflow)
+	}
+	if !m.isZero() {
+		res |= Inexact | Rounded
+		// m < d < 2^64, so 2m can't overflow.
+		m, _ = m.add(m)
+		var tmp BigInt
+		if r.ShouldAddOne(tmp.SetUint64(q.lo), neg, m.cmp(uint128{lo: d})) {
+			q = q.add64(1)
+		}
+	}
+	if q.hi != 0 || q.lo > math.MaxInt64+1 || (q.lo == math.MaxInt64+1 && !neg) {
+		return fixedError(res | Overflow)
+	}
+	units := int64(q.lo)
+	if neg {
+		units = -units
+	}
+	return Fixed{Units: units, Scale: scale}, res, nil
+}
+
+// alignFixed returns the units of x and y at the larger of their scales, and
+// that scale. It returns false if either of them overflows.
+func alignFixed(x, y Fixed) (a, b int64, scale uint8, ok bool) {
+	scale = maxScale(x, y)
+	if a, ok = scaleInt64(x.Units, scale-x.Scale); !ok {
+		return 0, 0, 0, false
+	}
+	if b, ok = scaleInt64(y.Units, scale-y.Scale); !ok {
+		return 0, 0, 0, false
+	}
+	return a, b, scale, true
+}
+
+// validFixedScales reports whether the scales of x and y are at most
+// MaxFixedScale.
+func validFixedScales(x, y Fixed) bool {
+	return x.Scale <= MaxFixedScale && y.Scale <= MaxFixedScale
+}
+
+func maxScale(x, y Fixed) uint8 {
+	if x.Scale > y.Scale {
+		return x.Scale
+	}
+	return y.Scale
+}
+
+// scaleInt64 returns a*10^n and whether it did not overflow.
+func scaleInt64(a int64, n uint8) (int64, bool) {
+	if n == 0 || a == 0 {
+		return a, true
+	}
+	if n > 19 {
+		// Any non-zero a times 10^20 overflows.
+		return 0, false
+	}
+	hi, lo := bits.Mul64(absInt64(a), pow10Uint128[n].lo)
+	if hi != 0 || lo > math.MaxInt64 {
+		return 0, false
+	}
+	if a < 0 {
+		return -int64(lo), true
+	}
+	return int64(lo), true
+}
+
+// absInt64 returns |a| as a uint64. It is correct for math.MinInt64.
+func absInt64(a int64) uint64 {
+	if a < 0 {
+		return uint64(-a)
+	}
+	return uint64(a)
+}
+
+// SetFixed sets d to x and returns d.
+func (d *Decimal) SetFixed(x Fixed) *Decimal {
+	d.Form = Finite
+	d.Negative = x.Units < 0
+	d.Coeff.SetUint64(absInt64(x.Units))
+	d.Exponent = -int32(x.Scale)
+	return d
+}
+
+// Fixed returns d as a Fixed with the given scale. An error is returned if d
+// is not finite or cannot be represented exactly with that scale.
+func (d *Decimal) Fixed(scale uint8) (Fixed, error) {
+	if scale > MaxFixedScale {
+		return Fixed{}, fmt.Errorf("scale %d greater than %d", scale, MaxFixedScale)
+	}
+	if d.Form != Finite {
+		return Fixed{}, fmt.Errorf("%s is not finite", d.String())
+	}
+	if d.IsZero() {
+		return Fixed{Scale: scale}, nil
+	}
+	if int64(d.Exponent)+int64(scale) > 19 {
+		// At least 20 digits, which don't fit in an int64.
+		return Fixed{}, fmt.Errorf("%s: out of range for scale %d", d.String(), scale)
+	}
+	var x Decimal
+	if res := BaseContext.quantize(&x, d, -int32(scale)); res.Inexact() {
+		return Fixed{}, fmt.Errorf("%s: cannot be represented with scale %d", d.String(), scale)
+	}
+	if !x.Coeff.IsUint64() {
+		return Fixed{}, fmt.Errorf("%s: out of range for scale %d", d.String(), scale)
+	}
+	u := x.Coeff.Uint64()
+	if u > math.MaxInt64+1 || (u == math.MaxInt64+1 && !d.Negative) {
+		return Fixed{}, fmt.Errorf("%s: out of range for scale %d", d.String(), scale)
+	}
+	units := int64(u)
+	if d.Negative {
+		units = -units
+	}
+	return Fixed{Units: units, Scale: scale}, nil
+}
+
+// Append appends to buf the string form of x, with exactly Scale digits after
+// the decimal point, and returns the extended buffer.
+func (x Fixed) Append(buf []byte) []byte {
+	if x.Units < 0 {
+		buf = append(buf, '-')
+	}
+	var scratch [20]byte
+	digits := strconv.AppendUint(scratch[:0], absInt64(x.Units), 10)
+	scale := int(x.Scale)
+	if scale == 0 {
+		return append(buf, digits...)
+	}
+	if left := len(digits) - scale; left > 0 {
+		buf = append(buf, digits[:left]...)
+		digits = digits[left:]
+	} else {
+		buf = append(buf, '0')
+	}
+	buf = append(buf, '.')
+	for i := len(digits); i < scale; i++ {
+		buf = append(buf, '0')
+	}
+	return append(buf, digits...)
+}
+
+// String returns the string form of x, with exactly Scale digits after the
+// decimal point.
+func (x Fixed) String() string {
+	var buf [24]byte
+	return string(x.Append(buf[:0]))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (x Fixed) MarshalText() ([]byte, error) {
+	return x.Append(nil), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. The scale
+// of x is set to the number of digits after the decimal point in text.
+func (x *Fixed) UnmarshalText(text []byte) error {
+	var d Decimal
+	if _, _, err := d.SetString(string(text)); err != nil {
+		return err
+	}
+	return x.setDecimal(&d)
+}
+
+// setDecimal sets x to d using the scale of d.
+func (x *Fixed) setDecimal(d *Decimal) error {
+	scale := uint8(0)
+	if d.Exponent < 0 {
+		if d.Exponent < -MaxFixedScale {
+			return fmt.Errorf("%s: scale greater than %d", d.String(), MaxFixedScale)
+		}
+		scale = uint8(-d.Exponent)
+	}
+	r, err := d.Fixed(scale)
+	if err != nil {
+		return err
+	}
+	*x = r
+	return nil
+}
+
+// Value implements the database/sql/driver.Valuer interface. It converts x to
+// a string.
+func (x Fixed) Value() (driver.Value, error) {
+	return x.String(), nil
+}
+
+// Scan implements the database/sql.Scanner interface. It supports string,
+// []byte and int64. The scale of x is set to the number of digits after the
+// decimal point in the source.
+func (x *Fixed) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return x.UnmarshalText(src)
+	case string:
+		return x.UnmarshalText([]byte(src))
+	case int64:
+		*x = Fixed{Units: src}
+		return nil
+	default:
+		return fmt.Errorf("could not convert %T to Fixed", src)
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFixedString(t *testing.T) {
+	tests := []struct {
+		x Fixed
+		s string
+	}{
+		{x: Fixed{}, s: "0"},
+		{x: Fixed{Scale: 2}, s: "0.00"},
+		{x: Fixed{Units: 5, Scale: 2}, s: "0.05"},
+		{x: Fixed{Units: -150, Scale: 2}, s: "-1.50"},
+		{x: Fixed{Units: -1, Scale: 3}, s: "-0.001"},
+		{x: Fixed{Units: 123400, Scale: 4}, s: "12.3400"},
+		{x: Fixed{Units: 42}, s: "42"},
+		{x: Fixed{Units: math.MinInt64, Scale: 18}, s: "-9.223372036854775808"},
+		{x: Fixed{Units: math.MaxInt64, Scale: 0}, s: "9223372036854775807"},
+	}
+	for _, tc := range tests {
+		if s := tc.x.String(); s != tc.s {
+			t.Fatalf("%+v: expected %s, got %s", tc.x, tc.s, s)
+		}
+		var x Fixed
+		if err := x.UnmarshalText([]byte(tc.s)); err != nil {
+			t.Fatal(err)
+		}
+		if x != tc.x {
+			t.Fatalf("%s: expected %+v, got %+v", tc.s, tc.x, x)
+		}
+	}
+}
+
+func TestFixedAddSub(t *testing.T) {
+	tests := []struct {
+		x, y     Fixed
+		add, sub string
+	}{
+		{x: Fixed{Units: 150, Scale: 2}, y: Fixed{Units: 25, Scale: 1}, add: "4.00", sub: "-1.00"},
+		{x: Fixed{Units: -1, Scale: 3}, y: Fixed{Units: 1, Scale: 0}, add: "0.999", sub: "-1.001"},
+		{x: Fixed{Units: math.MaxInt64, Scale: 0}, y: Fixed{Units: 1, Scale: 0}, add: "overflow", sub: "9223372036854775806"},
+		{x: Fixed{Units: math.MinInt64, Scale: 0}, y: Fixed{Units: 1, Scale: 0}, add: "-9223372036854775807", sub: "overflow"},
+		{x: Fixed{Units: math.MaxInt64/10 + 1, Scale: 0}, y: Fixed{Units: 0, Scale: 1}, add: "overflow", sub: "overflow"},
+		{x: Fixed{Units: 1, Scale: 0}, y: Fixed{Units: 0, Scale: 18}, add: "1.000000000000000000", sub: "1.000000000000000000"},
+	}
+	for _, tc := range tests {
+		for _, o := range []struct {
+			name string
+			op   func(x, y Fixed) (Fixed, Condition, error)
+			r    string
+		}{
+			{"add", Fixed.Add, tc.add},
+			{"sub", Fixed.Sub, tc.sub},
+		} {
+			z, res, err := o.op(tc.x, tc.y)
+			if o.r == "overflow" {
+				if err == nil || !res.Overflow() {
+					t.Fatalf("%s %s %s: expected overflow, got %s", tc.x, o.name, tc.y, z)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s %s %s: %v", tc.x, o.name, tc.y, err)
+			}
+			if res != 0 || z.String() != o.r {
+				t.Fatalf("%s %s %s: expected %s, got %s (%s)", tc.x, o.name, tc.y, o.r, z, res)
+			}
+		}
+	}
+}
+
+func TestFixedMulQuo(t *testing.T) {
+	tests := []struct {
+		op       string
+		x, y     Fixed
+		rounding Rounder
+		r        string
+		res      Condition
+	}{
+		{op: "mul", x: Fixed{Units: 150, Scale: 2}, y: Fixed{Units: 25, Scale: 1}, rounding: RoundHalfUp, r: "3.75", res: Rounded},
+		{op: "mul", x: Fixed{Units: 15, Scale: 1}, y: Fixed{Units: 15, Scale: 1}, rounding: RoundHalfUp, r: "2.3", res: Inexact | Rounded},
+		{op: "mul", x: Fixed{Units: 15, Scale: 1}, y: Fixed{Units: 15, Scale: 1}, rounding: RoundHalfEven, r: "2.2", res: Inexact | Rounded},
+		{op: "mul", x: Fixed{Units: -15, Scale: 1}, y: Fixed{Units: 15, Scale: 1}, rounding: RoundFloor, r: "-2.3", res: Inexact | Rounded},
+		{op: "mul", x: Fixed{Units: -15, Scale: 1}, y: Fixed{Units: 15, Scale: 1}, rounding: RoundDown, r: "-2.2", res: Inexact | Rounded},
+		{op: "mul", x: Fixed{Units: 3, Scale: 0}, y: Fixed{Units: 4, Scale: 0}, rounding: RoundHalfUp, r: "12"},
+		{op: "mul", x: Fixed{Units: math.MaxInt64, Scale: 0}, y: Fixed{Units: 2, Scale: 0}, rounding: RoundHalfUp, r: "overflow"},
+		{op: "mul", x: Fixed{Units: math.MinInt64, Scale: 0}, y: Fixed{Units: 1, Scale: 0}, rounding: RoundHalfUp, r: "-9223372036854775808"},
+		{op: "mul", x: Fixed{Units: math.MaxInt64, Scale: 18}, y: Fixed{Units: math.MaxInt64, Scale: 18}, rounding: RoundHalfUp, r: "overflow"},
+		{op: "mul", x: Fixed{Units: math.MaxInt64, Scale: 18}, y: Fixed{Units: 5, Scale: 1}, rounding: RoundHalfUp, r: "4.611686018427387904", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 1, Scale: 0}, y: Fixed{Units: 300, Scale: 2}, rounding: RoundHalfUp, r: "0.33", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 2, Scale: 0}, y: Fixed{Units: 300, Scale: 2}, rounding: RoundHalfUp, r: "0.67", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 2, Scale: 0}, y: Fixed{Units: 300, Scale: 2}, rounding: RoundDown, r: "0.66", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: -2, Scale: 0}, y: Fixed{Units: 300, Scale: 2}, rounding: RoundCeiling, r: "-0.66", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 10, Scale: 0}, y: Fixed{Units: 4, Scale: 0}, rounding: RoundHalfEven, r: "2", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 10, Scale: 1}, y: Fixed{Units: 4, Scale: 0}, rounding: RoundHalfEven, r: "0.2", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 10, Scale: 0}, y: Fixed{Units: 400, Scale: 2}, rounding: RoundHalfEven, r: "2.50"},
+		{op: "quo", x: Fixed{Units: math.MaxInt64, Scale: 0}, y: Fixed{Units: 1, Scale: 18}, rounding: RoundHalfUp, r: "overflow"},
+		{op: "quo", x: Fixed{Units: 7, Scale: 0}, y: Fixed{Units: 3, Scale: 18}, rounding: RoundHalfUp, r: "overflow"},
+		{op: "quo", x: Fixed{Units: math.MaxInt64, Scale: 0}, y: Fixed{Units: math.MaxInt64, Scale: 18}, rounding: RoundHalfUp, r: "overflow"},
+		{op: "quo", x: Fixed{Units: 1, Scale: 0}, y: Fixed{Units: math.MaxInt64, Scale: 18}, rounding: RoundHalfUp, r: "0.108420217248550443", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: -1, Scale: 0}, y: Fixed{Units: math.MaxInt64, Scale: 18}, rounding: RoundFloor, r: "-0.108420217248550444", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 1, Scale: 0}, y: Fixed{Units: 3, Scale: 0}, rounding: RoundHalfUp, r: "0", res: Inexact | Rounded},
+		{op: "quo", x: Fixed{Units: 1, Scale: 0}, y: Fixed{Units: 0, Scale: 2}, rounding: RoundHalfUp, r: "division by zero"},
+		{op: "quo", x: Fixed{Units: 0, Scale: 0}, y: Fixed{Units: 0, Scale: 2}, rounding: RoundHalfUp, r: "division undefined"},
+	}
+	for _, tc := range tests {
+		op := Fixed.Mul
+		if tc.op == "quo" {
+			op = Fixed.Quo
+		}
+		z, res, err := op(tc.x, tc.y, tc.rounding)
+		switch tc.r {
+		case "overflow", "division by zero", "division undefined":
+			if err == nil || err.Error() != tc.r {
+				t.Fatalf("%s %s %s: expected %s, got %s, %v", tc.x, tc.op, tc.y, tc.r, z, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s %s %s: %v", tc.x, tc.op, tc.y, err)
+		}
+		if z.String() != tc.r || res != tc.res {
+			t.Fatalf("%s %s %s: expected %s (%s), got %s (%s)", tc.x, tc.op, tc.y, tc.r, tc.res, z, res)
+		}
+	}
+}
+
+// TestFixedDifferential verifies that the Fixed operations produce the same
+// results as the Decimal operations quantized to the result scale.
+func TestFixedDifferential(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	randFixed := func() Fixed {
+		u := rng.Int63() >> uint(rng.Intn(63))
+		if rng.Intn(2) == 0 {
+			u = -u
+		}
+		return Fixed{Units: u, Scale: uint8(rng.Intn(MaxFixedScale + 1))}
+	}
+	var rounders []Rounder
+	for r := range roundings {
+		rounders = append(rounders, r)
+	}
+	c := BaseContext.WithPrecision(100)
+	for i := 0; i < 20000; i++ {
+		x, y := randFixed(), randFixed()
+		r := rounders[rng.Intn(len(rounders))]
+		c.Rounding = r
+		dx, dy := new(Decimal).SetFixed(x), new(Decimal).SetFixed(y)
+		var want Decimal
+		var z Fixed
+		var err error
+		switch rng.Intn(4) {
+		case 0:
+			c.Add(&want, dx, dy)
+			z, _, err = x.Add(y)
+		case 1:
+			c.Sub(&want, dx, dy)
+			z, _, err = x.Sub(y)
+		case 2:
+			c.Mul(&want, dx, dy)
+			z, _, err = x.Mul(y, r)
+		case 3:
+			if y.IsZero() {
+				continue
+			}
+			c.Quo(&want, dx, dy)
+			z, _, err = x.Quo(y, r)
+		}
+		scale := maxScale(x, y)
+		var expected Fixed
+		var wantErr error
+		if !want.IsZero() && int64(want.NumDigits())+int64(want.Exponent)+int64(scale) < 0 {
+			// Quantize sets the coefficient to 0 when all of its digits are
+			// discarded, regardless of the rounding mode, so determine the
+			// expected result directly: the discarded digits are less than
+			// half of the last place.
+			expected = Fixed{Scale: scale}
+			if r.ShouldAddOne(new(BigInt), want.Negative, -1) {
+				expected.Units = 1
+				if want.Negative {
+					expected.Units = -1
+				}
+			}
+		} else {
+			c.Quantize(&want, &want, -int32(scale))
+			expected, wantErr = want.Fixed(scale)
+		}
+		if (err != nil) != (wantErr != nil) {
+			t.Fatalf("%s, %s (%s): expected error %v, got %v", x, y, want.String(), wantErr, err)
+		}
+		if err == nil && z != expected {
+			t.Fatalf("%s, %s: expected %s, got %s", x, y, expected, z)
+		}
+	}
+}
+
+func TestFixedRescale(t *testing.T) {
+	tests := []struct {
+		x     Fixed
+		scale uint8
+		r     string
+		res   Condition
+	}{
+		{x: Fixed{Units: 12345, Scale: 3}, scale: 1, r: "12.3", res: Inexact | Rounded},
+		{x: Fixed{Units: 12350, Scale: 3}, scale: 1, r: "12.4", res: Inexact | Rounded},
+		{x: Fixed{Units: 12300, Scale: 3}, scale: 1, r: "12.3", res: Rounded},
+		{x: Fixed{Units: -5, Scale: 1}, scale: 0, r: "-1", res: Inexact | Rounded},
+		{x: Fixed{Units: 123, Scale: 1}, scale: 5, r: "12.30000"},
+		{x: Fixed{Units: math.MaxInt64, Scale: 0}, scale: 1, r: "overflow"},
+		{x: Fixed{Units: 1, Scale: 0}, scale: MaxFixedScale + 1, r: "invalid operation"},
+		{x: Fixed{Units: 1, Scale: MaxFixedScale + 1}, scale: 0, r: "invalid operation"},
+	}
+	for _, tc := range tests {
+		z, res, err := tc.x.Rescale(tc.scale, RoundHalfUp)
+		if tc.r == "overflow" || tc.r == "invalid operation" {
+			if err == nil || err.Error() != tc.r {
+				t.Fatalf("%s: expected %s, got %s, %v", tc.x, tc.r, z, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if z.String() != tc.r || res != tc.res {
+			t.Fatalf("%s: expected %s (%s), got %s (%s)", tc.x, tc.r, tc.res, z, res)
+		}
+	}
+}
+
+func TestFixedInvalidScale(t *testing.T) {
+	if _, err := NewFixed(1, MaxFixedScale+1); err == nil {
+		t.Fatal("expected error")
+	}
+	if x, err := NewFixed(-5, MaxFixedScale); err != nil || x != (Fixed{Units: -5, Scale: MaxFixedScale}) {
+		t.Fatalf("unexpected %s, %v", x, err)
+	}
+	valid := Fixed{Units: 1, Scale: 2}
+	for _, bad := range []Fixed{{Units: 1, Scale: 20}, {Units: 1, Scale: 30}, {Scale: 255}} {
+		for _, pair := range [][2]Fixed{{bad, bad}, {bad, valid}, {valid, bad}} {
+			x, y := pair[0], pair[1]
+			ops := map[string]func() (Fixed, Condition, error){
+				"add": func() (Fixed, Condition, error) { return x.Add(y) },
+				"sub": func() (Fixed, Condition, error) { return x.Sub(y) },
+				"mul": func() (Fixed, Condition, error) { return x.Mul(y, RoundHalfUp) },
+				"quo": func() (Fixed, Condition, error) { return x.Quo(y, RoundHalfUp) },
+			}
+			for name, op := range ops {
+				if z, res, err := op(); res != InvalidOperation || err == nil {
+					t.Errorf("%s %s %s: expected invalid operation, got %s %s %v", x, name, y, z, res, err)
+				}
+			}
+		}
+		// Cmp has no error result, but still orders the values.
+		if c := bad.Cmp(valid); c != -1 {
+			t.Errorf("%s cmp %s: expected -1, got %d", bad, valid, c)
+		}
+		if c := (Fixed{Units: 1}).Cmp(bad); c != 1 {
+			t.Errorf("1 cmp %s: expected 1, got %d", bad, c)
+		}
+	}
+}
+
+func TestFixedCmp(t *testing.T) {
+	tests := []struct {
+		x, y Fixed
+		c    int
+	}{
+		{x: Fixed{Units: 10, Scale: 1}, y: Fixed{Units: 1, Scale: 0}, c: 0},
+		{x: Fixed{Units: 0, Scale: 5}, y: Fixed{Units: 0, Scale: 0}, c: 0},
+		{x: Fixed{Units: -1, Scale: 0}, y: Fixed{Units: 1, Scale: 18}, c: -1},
+		{x: Fixed{Units: math.MaxInt64, Scale: 0}, y: Fixed{Units: math.MaxInt64, Scale: 18}, c: 1},
+		{x: Fixed{Units: math.MinInt64, Scale: 0}, y: Fixed{Units: math.MinInt64, Scale: 18}, c: -1},
+		{x: Fixed{Units: -15, Scale: 1}, y: Fixed{Units: -149, Scale: 2}, c: -1},
+	}
+	for _, tc := range tests {
+		if c := tc.x.Cmp(tc.y); c != tc.c {
+			t.Fatalf("%s cmp %s: expected %d, got %d", tc.x, tc.y, tc.c, c)
+		}
+		if c := tc.y.Cmp(tc.x); c != -tc.c {
+			t.Fatalf("%s cmp %s: expected %d, got %d", tc.y, tc.x, -tc.c, c)
+		}
+	}
+	if _, _, err := (Fixed{Units: math.MinInt64}).Neg(); err == nil {
+		t.Fatal("expected overflow")
+	}
+}
+
+func TestFixedDecimal(t *testing.T) {
+	tests := []struct {
+		s     string
+		scale uint8
+		r     string
+	}{
+		{s: "1.5", scale: 2, r: "1.50"},
+		{s: "-0", scale: 3, r: "0.000"},
+		{s: "1.2E+3", scale: 1, r: "1200.0"},
+		{s: "-9.223372036854775808", scale: 18, r: "-9.223372036854775808"},
+		{s: "9.223372036854775808", scale: 18},
+		{s: "1E+19", scale: 0},
+		{s: "1E+100", scale: 0},
+		{s: "1.25", scale: 1},
+		{s: "1.20", scale: 1, r: "1.2"},
+		{s: "1E-100", scale: 18},
+		{s: "NaN", scale: 0},
+		{s: "Inf", scale: 0},
+		{s: "1", scale: MaxFixedScale + 1},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		x, err := d.Fixed(tc.scale)
+		if tc.r == "" {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %s", tc.s, x)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if x.String() != tc.r {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.r, x)
+		}
+		var r Decimal
+		if r.SetFixed(x).Cmp(d) != 0 {
+			t.Fatalf("%s: round trip gave %s", tc.s, &r)
+		}
+	}
+}
+
+func TestFixedScan(t *testing.T) {
+	tests := []struct {
+		src interface{}
+		r   string
+	}{
+		{src: "12.340", r: "12.340"},
+		{src: []byte("-0.5"), r: "-0.5"},
+		{src: int64(-7), r: "-7"},
+		{src: "1E-19"},
+		{src: "abc"},
+		{src: 1.5},
+	}
+	for _, tc := range tests {
+		var x Fixed
+		err := x.Scan(tc.src)
+		if tc.r == "" {
+			if err == nil {
+				t.Fatalf("%v: expected error", tc.src)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.String() != tc.r {
+			t.Fatalf("expected %s, got %s", tc.r, x)
+		}
+		v, err := x.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != tc.r {
+			t.Fatalf("expected %s, got %v", tc.r, v)
+		}
+	}
+}
+
+func TestFixedAllocs(t *testing.T) {
+	x := Fixed{Units: 123456789, Scale: 4}
+	y := Fixed{Units: -98765, Scale: 2}
+	var buf [32]byte
+	allocs := testing.AllocsPerRun(100, func() {
+		x.Add(y)
+		x.Sub(y)
+		x.Mul(y, RoundHalfEven)
+		x.Quo(y, RoundHalfEven)
+		x.Rescale(1, RoundHalfUp)
+		x.Cmp(y)
+		x.Append(buf[:0])
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %f", allocs)
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// SetFloat64Exact sets d to the exact value of f and returns d. Unlike
+// SetFloat64, which uses the shortest decimal that rounds to f, the result has
+// all digits of the binary value, so 0.1 is
+// 0.1000000000000000055511151231257827021181583404541015625.
+func (d *Decimal) SetFloat64Exact(f float64) *Decimal {
+	switch {
+	case math.IsNaN(f):
+		return d.setSpecial(NaN, false)
+	case math.IsInf(f, 0):
+		return d.setSpecial(Infinite, f < 0)
+	}
+	b := math.Float64bits(f)
+	exp := int(b>>52) & 0x7ff
+	mant := b & (1<<52 - 1)
+	if exp == 0 {
+		// Subnormal.
+		exp = 1
+	} else {
+		mant |= 1 << 52
+	}
+	d.setBinary(mant, exp-1075, b>>63 != 0)
+	return d
+}
+
+// SetFloat32 sets d to the exact value of f and returns d.
+func (d *Decimal) SetFloat32(f float32) *Decimal {
+	b := math.Float32bits(f)
+	exp := int(b>>23) & 0xff
+	mant := b & (1<<23 - 1)
+	switch {
+	case exp == 0xff && mant != 0:
+		return d.setSpecial(NaN, false)
+	case exp == 0xff:
+		return d.setSpecial(Infinite, b>>31 != 0)
+	case exp == 0:
+		// Subnormal.
+		exp = 1
+	default:
+		mant |= 1 << 23
+	}
+	d.setBinary(uint64(mant), exp-150, b>>31 != 0)
+	return d
+}
+
+func (d *Decimal) setSpecial(form Form, neg bool) *Decimal {
+	d.Form = form
+	d.Negative = neg
+	d.Exponent = 0
+	d.Coeff.SetInt64(0)
+	return d
+}
+
+// setBinary sets d to mant × 2**exp.
+func (d *Decimal) setBinary(mant uint64, exp int, neg bool) {
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = 0
+	if mant == 0 {
+		d.Coeff.SetInt64(0)
+		return
+	}
+	tz := bits.TrailingZeros64(mant)
+	mant >>= tz
+	exp += tz
+	if exp >= 0 {
+		d.Coeff.SetUint64(mant)
+		d.Coeff.Lsh(&d.Coeff, uint(exp))
+		return
+	}
+	// mant × 2**exp is mant × 5**-exp × 10**exp.
+	d.Exponent = int32(exp)
+	if exp >= -maxPow5Uint64 {
+		p := uint64(1)
+		for i := 0; i < -exp; i++ {
+			p *= 5
+		}
+		hi, lo := bits.Mul64(mant, p)
+		uint128{hi: hi, lo: lo}.bigInt(&d.Coeff)
+		return
+	}
+	var p BigInt
+	p.Exp(bigFive, NewBigInt(int64(-exp)), nil)
+	d.Coeff.SetUint64(mant)
+	d.Coeff.Mul(&d.Coeff, &p)
+}
+
+// maxPow5Uint64 is the largest power of 5 that fits in a uint64.
+const maxPow5Uint64 = 27
+
+// floatFormat describes an IEEE 754 binary format.
+type floatFormat struct {
+	// mantBits is the number of bits of the significand, including the
+	// implicit bit.
+	mantBits uint
+	// minExp and maxExp are the smallest and largest exponents e of a value
+	// m × 2**e with a mantBits-bit integer m.
+	minExp, maxExp int
+	// minAdj and maxAdj bound the adjusted exponents of decimals that are
+	// neither negligible nor overflow.
+	minAdj, maxAdj int64
+	max            float64
+}
+
+var (
+	float64Format = floatFormat{mantBits: 53, minExp: -1074, maxExp: 971, minAdj: -324, maxAdj: 308, max: math.MaxFloat64}
+	float32Format = floatFormat{mantBits: 24, minExp: -149, maxExp: 104, minAdj: -46, maxAdj: 38, max: math.MaxFloat32}
+)
+
+// float64Pow10 holds the powers of ten that are exact float64 values.
+var float64Pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11,
+	1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22,
+}
+
+// Float64 returns x rounded to a float64 with the rounding mode of c. The
+// precision and exponent limits of c are not used. The result conditions
+// include Inexact and Rounded if the result is not exact, Overflow if x is
+// beyond the finite float64 range, in which case the result is an infinity or
+// the largest float64 depending on the rounding mode, and Subnormal and
+// Underflow if the result is subnormal or zero. A signaling NaN converts to
+// NaN with InvalidOperation.
+func (c *Context) Float64(x *Decimal) (float64, Condition, error) {
+	f, res := x.toFloat(c.Rounding, &float64Format)
+	_, err := c.goError(res)
+	return f, res, err
+}
+
+// Float32 is like Float64, but returns a float32.
+func (c *Context) Float32(x *Decimal) (float32, Condition, error) {
+	f, res := x.toFloat(c.Rounding, &float32Format)
+	_, err := c.goError(res)
+	return float32(f), res, err
+}
+
+// toFloat returns d rounded with r to a value of the format ff, which is
+// exactly representable as a float64.
+func (d *Decimal) toFloat(r Rounder, ff *floatFormat) (float64, Condition) {
+	switch d.Form {
+	case NaN:
+		return math.NaN(), 0
+	case NaNSignaling:
+		return math.NaN(), InvalidOperation
+	case Infinite:
+		if d.Negative {
+			return math.Inf(-1), 0
+		}
+		return math.Inf(1), 0
+	}
+	if d.IsZero() {
+		if d.Negative {
+			return math.Copysign(0, -1), 0
+		}
+		return 0, 0
+	}
+	if f, res, ok := d.toFloatFast(r, ff); ok {
+		return f, res
+	}
+
+	adj := int64(d.Exponent) + d.NumDigits() - 1
+	if adj > ff.maxAdj {
+		return ff.overflow(r, d.Negative)
+	}
+	var q uint64
+	e := ff.minExp
+	half := -1
+	if adj >= ff.minAdj {
+		// d is num/den. Find e such that q = num / (den × 2**e) has mantBits
+		// bits, or e = minExp for subnormals, and compare the remainder with
+		// half of the divisor.
+		num := d.Coeff.MathBigInt()
+		den := big.NewInt(1)
+		if d.Exponent >= 0 {
+			num.Mul(num, bigIntPow10(int64(d.Exponent)))
+		} else {
+			den = bigIntPow10(-int64(d.Exponent))
+		}
+		e = num.BitLen() - den.BitLen() - int(ff.mantBits)
+		if e < ff.minExp {
+			e = ff.minExp
+		}
+		var n, m, qb, rb big.Int
+		for {
+			n.Set(num)
+			m.Set(den)
+			if e < 0 {
+				n.Lsh(&n, uint(-e))
+			} else {
+				m.Lsh(&m, uint(e))
+			}
+			qb.QuoRem(&n, &m, &rb)
+			if uint(qb.BitLen()) <= ff.mantBits {
+				break
+			}
+			e++
+		}
+		q = qb.Uint64()
+		if rb.Sign() == 0 {
+			half = -2
+		} else {
+			half = rb.Lsh(&rb, 1).Cmp(&m)
+		}
+	}
+
+	var res Condition
+	if half != -2 {
+		res |= Inexact | Rounded
+		var qb BigInt
+		qb.SetUint64(q)
+		if r.ShouldAddOne(&qb, d.Negative, half) {
+			q++
+			if q == 1<<ff.mantBits {
+				q >>= 1
+				e++
+			}
+		}
+	}
+	if e > ff.maxExp {
+		return ff.overflow(r, d.Negative)
+	}
+	if q < 1<<(ff.mantBits-1) {
+		res |= Subnormal
+		if res&Inexact != 0 {
+			res |= Underflow
+		}
+	}
+	f := math.Ldexp(float64(q), e)
+	if d.Negative {
+		f = -f
+	}
+	return f, res
+}
+
+// toFloatFast converts d without big arithmetic if it is exact or if r rounds
+// to nearest and d has a small coefficient and exponent.
+func (d *Decimal) toFloatFast(r Rounder, ff *floatFormat) (float64, Condition, bool) {
+	if !d.Coeff.IsUint64() {
+		return 0, 0, false
+	}
+	c := d.Coeff.Uint64()
+	if c >= 1<<ff.mantBits || d.Exponent < -maxPow5Uint64 || d.Exponent > 22 {
+		return 0, 0, false
+	}
+	var f float64
+	var res Condition
+	if d.Exponent >= 0 {
+		hi, lo := bits.Mul64(c, uint64(float64Pow10[d.Exponent]))
+		if hi != 0 || lo >= 1<<ff.mantBits {
+			return 0, 0, false
+		}
+		f = float64(lo)
+	} else {
+		k := int(-d.Exponent)
+		p := uint64(1)
+		for i := 0; i < k; i++ {
+			p *= 5
+		}
+		if c%p == 0 {
+			// c / 10**k is (c / 5**k) × 2**-k.
+			f = math.Ldexp(float64(c/p), -k)
+		} else {
+			// c / 10**k is not a dyadic rational, so it is not exact or
+			// halfway between two floats. Division rounds it correctly to
+			// nearest, for any tie-breaking rule.
+			switch r {
+			case RoundHalfEven, RoundHalfUp, RoundHalfDown, "":
+			default:
+				return 0, 0, false
+			}
+			if ff != &float64Format || k >= len(float64Pow10) {
+				return 0, 0, false
+			}
+			f = float64(c) / float64Pow10[k]
+			res = Inexact | Rounded
+		}
+	}
+	if d.Negative {
+		f = -f
+	}
+	return f, res, true
+}
+
+// overflow returns the result of rounding a value beyond the largest finite
+// value of ff.
+func (ff *floatFormat) overflow(r Rounder, neg bool) (float64, Condition) {
+	var q BigInt
+	q.SetUint64(1<<ff.mantBits - 1)
+	f := math.Inf(1)
+	if !r.ShouldAddOne(&q, neg, 1) {
+		f = ff.max
+	}
+	if neg {
+		f = -f
+	}
+	return f, Overflow | Inexact | Rounded
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestSetFloat64Exact(t *testing.T) {
+	tests := []struct {
+		f        float64
+		expected string
+	}{
+		{f: 0, expected: "0"},
+		{f: math.Copysign(0, -1), expected: "-0"},
+		{f: 1, expected: "1"},
+		{f: -2.5, expected: "-2.5"},
+		{f: 0.1, expected: "0.1000000000000000055511151231257827021181583404541015625"},
+		{f: 1e23, expected: "99999999999999991611392"},
+		{f: 1 << 70, expected: "1180591620717411303424"},
+		{f: 0x1p-30, expected: "9.31322574615478515625E-10"},
+		{f: math.NaN(), expected: "NaN"},
+		{f: math.Inf(1), expected: "Infinity"},
+		{f: math.Inf(-1), expected: "-Infinity"},
+	}
+	for _, tc := range tests {
+		var d Decimal
+		if s := d.SetFloat64Exact(tc.f).String(); s != tc.expected {
+			t.Errorf("%g: expected %s, got %s", tc.f, tc.expected, s)
+		}
+	}
+
+	// Compare against big.Rat, which is also exact.
+	rnd := rand.New(rand.NewSource(0))
+	floats := []float64{
+		math.MaxFloat64, math.SmallestNonzeroFloat64, 0x1p-1022, 0x1p-1022 - 0x1p-1074,
+		1 << 53, 1<<53 + 2, 123.456, 1e-300,
+	}
+	for i := 0; i < 2000; i++ {
+		floats = append(floats, math.Float64frombits(rnd.Uint64()))
+	}
+	for _, f := range floats {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		var d Decimal
+		r, err := d.SetFloat64Exact(f).Rat(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(new(big.Rat).SetFloat64(f)) != 0 {
+			t.Fatalf("%g: inexact result %s", f, &d)
+		}
+		if d.Negative != math.Signbit(f) {
+			t.Fatalf("%g: wrong sign %s", f, &d)
+		}
+	}
+}
+
+func TestSetFloat32(t *testing.T) {
+	for _, f := range []float32{
+		0, float32(math.Copysign(0, -1)), 0.1, -1.5, 16777217,
+		math.MaxFloat32, -math.SmallestNonzeroFloat32, 1.1754942e-38,
+	} {
+		var d Decimal
+		r, err := d.SetFloat32(f).Rat(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(new(big.Rat).SetFloat64(float64(f))) != 0 {
+			t.Errorf("%g: inexact result %s", f, &d)
+		}
+		if d.Negative != math.Signbit(float64(f)) {
+			t.Errorf("%g: wrong sign %s", f, &d)
+		}
+		// The exact value converts back exactly.
+		c := Context{Rounding: RoundHalfEven}
+		if g, _, err := c.Float32(&d); err != nil || g != f {
+			t.Errorf("%g: round trip got %g, %v", f, g, err)
+		}
+	}
+	var d Decimal
+	if d.SetFloat32(float32(math.NaN())).Form != NaN {
+		t.Errorf("expected NaN, got %s", &d)
+	}
+	if d.SetFloat32(float32(math.Inf(-1))); d.Form != Infinite || !d.Negative {
+		t.Errorf("expected -Infinity, got %s", &d)
+	}
+}
+
+var floatRoundings = []struct {
+	r    Rounder
+	mode big.RoundingMode
+}{
+	{RoundHalfEven, big.ToNearestEven},
+	{RoundHalfUp, big.ToNearestAway},
+	{RoundDown, big.ToZero},
+	{RoundUp, big.AwayFromZero},
+	{RoundCeiling, big.ToPositiveInf},
+	{RoundFloor, big.ToNegativeInf},
+}
+
+// TestContextFloat compares conversions with big.Float, which rounds correctly
+// in all of these modes for values in the normal range.
+func TestContextFloat(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var inputs []string
+	for _, s := range []string{
+		"1", "-1", "0.1", "1.5", "2.5", "-0.3", "9007199254740993", "9007199254740995",
+		"1.7976931348623157E+308", "2.2250738585072014E-308", "3.4028235E+38",
+		"1.17549435E-38", "16777217", "123456789012345678901234567890E-20",
+		"0.30000000000000001665", "4.35E-15", "1E+22", "1E+23",
+	} {
+		inputs = append(inputs, s)
+	}
+	for i := 0; i < 3000; i++ {
+		digits := rnd.Intn(25) + 1
+		coeff := make([]byte, digits)
+		for j := range coeff {
+			coeff[j] = byte('0' + rnd.Intn(10))
+		}
+		inputs = append(inputs, fmt.Sprintf("%s%sE%d", []string{"", "-"}[rnd.Intn(2)], coeff, rnd.Intn(80)-40-digits))
+	}
+	for _, s := range inputs {
+		d := newDecimal(t, testCtx, s)
+		if d.IsZero() {
+			continue
+		}
+		exact, err := d.Rat(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, fr := range floatRoundings {
+			c := Context{Rounding: fr.r}
+
+			f64, res, err := c.Float64(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bf := new(big.Float).SetPrec(53).SetMode(fr.mode).SetRat(exact)
+			expected, _ := bf.Float64()
+			if f64 != expected {
+				t.Fatalf("%s %s: expected %v, got %v", s, fr.r, expected, f64)
+			}
+			isExact := new(big.Rat).SetFloat64(f64).Cmp(exact) == 0
+			if res.Inexact() == isExact {
+				t.Fatalf("%s %s: unexpected conditions %s", s, fr.r, res)
+			}
+
+			f32, res, err := c.Float32(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Overflow() || res.Subnormal() {
+				// big.Float has no float32 overflow or subnormals.
+				continue
+			}
+			bf = new(big.Float).SetPrec(24).SetMode(fr.mode).SetRat(exact)
+			expected32, _ := bf.Float32()
+			if f32 != expected32 {
+				t.Fatalf("%s %s: expected float32 %v, got %v", s, fr.r, expected32, f32)
+			}
+			isExact = new(big.Rat).SetFloat64(float64(f32)).Cmp(exact) == 0
+			if res.Inexact() == isExact {
+				t.Fatalf("%s %s: unexpected float32 conditions %s", s, fr.r, res)
+			}
+		}
+		// Half-even agrees with strconv.
+		c := Context{Rounding: RoundHalfEven}
+		f, _, _ := c.Float64(d)
+		if expected, _ := strconv.ParseFloat(s, 64); f != expected {
+			t.Fatalf("%s: expected %v from strconv, got %v", s, expected, f)
+		}
+	}
+}
+
+func TestContextFloatEdgeCases(t *testing.T) {
+	const inexact = Inexact | Rounded
+	tests := []struct {
+		s        string
+		r        Rounder
+		f32      bool
+		expected float64
+		res      Condition
+	}{
+		{s: "0", expected: 0},
+		{s: "-0", expected: math.Copysign(0, -1)},
+		{s: "0E-500", expected: 0},
+		{s: "NaN", expected: math.NaN()},
+		{s: "sNaN", expected: math.NaN(), res: InvalidOperation},
+		{s: "Infinity", expected: math.Inf(1)},
+		{s: "-Infinity", expected: math.Inf(-1)},
+		{s: "1E+309", expected: math.Inf(1), res: Overflow | inexact},
+		{s: "-1E+309", expected: math.Inf(-1), res: Overflow | inexact},
+		{s: "1E+309", r: RoundDown, expected: math.MaxFloat64, res: Overflow | inexact},
+		{s: "-1E+309", r: RoundCeiling, expected: -math.MaxFloat64, res: Overflow | inexact},
+		{s: "-1E+309", r: RoundFloor, expected: math.Inf(-1), res: Overflow | inexact},
+		{s: "1.7976931348623158E+308", expected: math.MaxFloat64, res: inexact},
+		{s: "1.7976931348623159E+308", expected: math.Inf(1), res: Overflow | inexact},
+		{s: "1E+100000", expected: math.Inf(1), res: Overflow | inexact},
+		{s: "5E-324", expected: math.SmallestNonzeroFloat64, res: Subnormal | Underflow | inexact},
+		{s: "2E-324", expected: 0, res: Subnormal | Underflow | inexact},
+		{s: "2E-324", r: RoundUp, expected: math.SmallestNonzeroFloat64, res: Subnormal | Underflow | inexact},
+		{s: "-1E-400", expected: math.Copysign(0, -1), res: Subnormal | Underflow | inexact},
+		{s: "-1E-400", r: RoundFloor, expected: -math.SmallestNonzeroFloat64, res: Subnormal | Underflow | inexact},
+		{s: "1E-100000", r: RoundCeiling, expected: math.SmallestNonzeroFloat64, res: Subnormal | Underflow | inexact},
+		{s: "2.4703282292062327208828439643411068618252990130716238221279284125033775363510437593264991818081799618989828234772285886546332835517796989819938739800539093906315035659515570226392290858392449105184435931802849936536152500319370457678249219365623669863658480757001585769269903706311928279558551332927834338409351978015531246597263579574622766465272827220056374006485499977096599470454020828166226237857393450736339007967761930577506740176324673600968951340535537458516661134223766678604162159680461914467291840300530057530849048765391711386591646239524912623653881879636239373280423891018672348497668235089863388587925628302755995657524455507255189313690836254779186948667994968324049705821028513185451396213837722826145437693412532098591327667236328125E-324",
+			r: RoundHalfEven, expected: 0, res: Subnormal | Underflow | inexact},
+		{s: "2.4703282292062327208828439643411068618252990130716238221279284125033775363510437593264991818081799618989828234772285886546332835517796989819938739800539093906315035659515570226392290858392449105184435931802849936536152500319370457678249219365623669863658480757001585769269903706311928279558551332927834338409351978015531246597263579574622766465272827220056374006485499977096599470454020828166226237857393450736339007967761930577506740176324673600968951340535537458516661134223766678604162159680461914467291840300530057530849048765391711386591646239524912623653881879636239373280423891018672348497668235089863388587925628302755995657524455507255189313690836254779186948667994968324049705821028513185451396213837722826145437693412532098591327667236328125E-324",
+			r: RoundHalfUp, expected: math.SmallestNonzeroFloat64, res: Subnormal | Underflow | inexact},
+		{s: "2.5", r: RoundHalfDown, expected: 2.5},
+		{s: "9007199254740993", r: RoundHalfDown, expected: 9007199254740992, res: inexact},
+		{s: "9007199254740993", r: RoundHalfUp, expected: 9007199254740994, res: inexact},
+		{s: "4.9406564584124654E-324", expected: math.SmallestNonzeroFloat64, res: Subnormal | Underflow | inexact},
+		{s: "1E-323", expected: 1e-323, res: Subnormal | Underflow | inexact},
+		{s: "3.4028236E+38", f32: true, expected: math.Inf(1), res: Overflow | inexact},
+		{s: "3.4028236E+38", f32: true, r: RoundDown, expected: math.MaxFloat32, res: inexact},
+		{s: "3.5E+38", f32: true, r: RoundDown, expected: math.MaxFloat32, res: Overflow | inexact},
+		{s: "1E-45", f32: true, expected: math.SmallestNonzeroFloat32, res: Subnormal | Underflow | inexact},
+		{s: "1E-50", f32: true, expected: 0, res: Subnormal | Underflow | inexact},
+		{s: "0.5", f32: true, expected: 0.5},
+	}
+	for _, tc := range tests {
+		c := Context{Rounding: tc.r}
+		d := newDecimal(t, testCtx, tc.s)
+		var f float64
+		var res Condition
+		if tc.f32 {
+			f32, r, err := c.Float32(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, res = float64(f32), r
+		} else {
+			var err error
+			f, res, err = c.Float64(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		same := f == tc.expected && math.Signbit(f) == math.Signbit(tc.expected)
+		if math.IsNaN(tc.expected) {
+			same = math.IsNaN(f)
+		}
+		if !same || res != tc.res {
+			t.Errorf("%.40s %s: expected %v %s, got %v %s", tc.s, tc.r, tc.expected, tc.res, f, res)
+		}
+	}
+
+	// Conditions are trapped like other operations.
+	if _, _, err := BaseContext.Float64(newDecimal(t, testCtx, "1E+400")); err == nil {
+		t.Fatal("expected overflow error")
+	}
+}
+
+func TestContextFloat64Allocs(t *testing.T) {
+	c := Context{Rounding: RoundHalfEven}
+	for _, s := range []string{"1.5", "-123.456", "0.1", "12345678E+5"} {
+		d := newDecimal(t, testCtx, s)
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, _, err := c.Float64(d); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %.1f", s, allocs)
+		}
+	}
+}
@@ -456,7 +456,7 @@ func gdaTest(t *testing.T, path string, tcs []TestCase) {
 					switch tc.Operation {
 					case "tosci":
 						// Skip cases with exponents larger than we will parse.
-						if strings.Contains(err.Error(), "value out of range") {
+						if strings.Contains(err.Error(), errExponentOutOfRangeStr) {
 							return
 						}
 					}
@@ -708,7 +708,13 @@ func gdaTest(t *testing.T, path string, tcs []TestCase) {
 				}
 				return
 			}
-			r := newDecimal(t, testCtx, tc.Result)
+			// Results can be subnormal below the package limits.
+			rctx := c.WithPrecision(1000)
+			if c.Precision > rctx.Precision {
+				rctx.Precision = c.Precision
+			}
+			rctx.Traps = 0
+			r := newDecimal(t, rctx, tc.Result)
 			var equal bool
 			if d.Form == Finite {
 				// Don't worry about trailing zeros being inequal in CmpTotal.
@@ -0,0 +1,253 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// SetUint64 sets d to x and returns d.
+func (d *Decimal) SetUint64(x uint64) *Decimal {
+	d.Form = Finite
+	d.Negative = false
+	d.Exponent = 0
+	d.Coeff.SetUint64(x)
+	return d
+}
+
+// Uint64 returns the uint64 representation of x. If x cannot be represented
+// in a uint64, an error is returned. Context.Uint64 rounds away a fractional
+// part.
+func (d *Decimal) Uint64() (uint64, error) {
+	if d.Form != Finite {
+		return 0, fmt.Errorf("%s is not finite", d.String())
+	}
+	v, neg, res := d.roundUint64(RoundDown)
+	if res.Inexact() {
+		return 0, fmt.Errorf("%s: has fractional part", d.String())
+	}
+	if res.InvalidOperation() {
+		return 0, fmt.Errorf("%s: greater than max uint64", d.String())
+	}
+	if neg && v != 0 {
+		return 0, fmt.Errorf("%s: less than zero", d.String())
+	}
+	return v, nil
+}
+
+// Int64 returns x rounded to an integer with the rounding mode of c. The
+// precision and exponent limits of c are not used. The result conditions
+// include Inexact and Rounded if x has a nonzero fractional part. If x is not
+// finite or the rounded value is out of range, the result is 0 with
+// InvalidOperation.
+func (c *Context) Int64(x *Decimal) (int64, Condition, error) {
+	v, res := x.roundInt(c.Rounding, math.MaxInt64, 1<<63)
+	_, err := c.goError(res)
+	return v, res, err
+}
+
+// Int32 is like Int64, but returns an int32.
+func (c *Context) Int32(x *Decimal) (int32, Condition, error) {
+	v, res := x.roundInt(c.Rounding, math.MaxInt32, 1<<31)
+	_, err := c.goError(res)
+	return int32(v), res, err
+}
+
+// Uint64 is like Int64, but returns a uint64. Negative values that round to
+// zero are in range.
+func (c *Context) Uint64(x *Decimal) (uint64, Condition, error) {
+	v, neg, res := x.roundUint64(c.Rounding)
+	if neg && v != 0 {
+		v, res = 0, InvalidOperation
+	}
+	_, err := c.goError(res)
+	return v, res, err
+}
+
+// Uint32 is like Uint64, but returns a uint32.
+func (c *Context) Uint32(x *Decimal) (uint32, Condition, error) {
+	v, neg, res := x.roundUint64(c.Rounding)
+	if (neg && v != 0) || v > math.MaxUint32 {
+		v, res = 0, InvalidOperation
+	}
+	_, err := c.goError(res)
+	return uint32(v), res, err
+}
+
+// BigInt sets z to x rounded to an integer with the rounding mode of c, and
+// returns z. If z is nil, a new BigInt is allocated. The result conditions are
+// as in Int64, but no finite value is out of range. Note that the integer
+// value of x has x.Exponent more digits than its coefficient.
+func (c *Context) BigInt(z *BigInt, x *Decimal) (*BigInt, Condition, error) {
+	if z == nil {
+		z = new(BigInt)
+	}
+	res, err := x.roundBigInt(c.Rounding, z)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, err = c.goError(res)
+	return z, res, err
+}
+
+// MathBigInt is like BigInt, but for a math/big.Int.
+func (c *Context) MathBigInt(z *big.Int, x *Decimal) (*big.Int, Condition, error) {
+	if z == nil {
+		z = new(big.Int)
+	}
+	var b BigInt
+	res, err := x.roundBigInt(c.Rounding, &b)
+	if err != nil {
+		return nil, 0, err
+	}
+	z.Set(b.inner(new(big.Int)))
+	_, err = c.goError(res)
+	return z, res, err
+}
+
+// roundInt returns d rounded with r to an integer in [-maxNeg, maxPos].
+func (d *Decimal) roundInt(r Rounder, maxPos, maxNeg uint64) (int64, Condition) {
+	v, neg, res := d.roundUint64(r)
+	if neg {
+		if v > maxNeg {
+			return 0, InvalidOperation
+		}
+		// For v == 1<<63 this wraps to math.MinInt64, as intended.
+		return -int64(v), res
+	}
+	if v > maxPos {
+		return 0, InvalidOperation
+	}
+	return int64(v), res
+}
+
+// roundUint64 returns the magnitude and sign of d rounded with r to an
+// integer. It returns InvalidOperation if d is not finite or the magnitude
+// does not fit in a uint64.
+func (d *Decimal) roundUint64(r Rounder) (v uint64, neg bool, res Condition) {
+	if d.Form != Finite {
+		return 0, false, InvalidOperation
+	}
+	neg = d.Negative
+	if !d.Coeff.IsUint64() {
+		if d.Exponent >= 0 {
+			return 0, false, InvalidOperation
+		}
+		var z BigInt
+		// d has a negative exponent, so roundBigInt doesn't fail.
+		res, _ = d.roundBigInt(r, &z)
+		z.Abs(&z)
+		if !z.IsUint64() {
+			return 0, false, InvalidOperation
+		}
+		return z.Uint64(), neg, res
+	}
+	c := d.Coeff.Uint64()
+	if c == 0 {
+		return 0, neg, 0
+	}
+	if d.Exponent >= 0 {
+		for i := int32(0); i < d.Exponent; i++ {
+			hi, lo := bits.Mul64(c, 10)
+			if hi != 0 {
+				return 0, false, InvalidOperation
+			}
+			c = lo
+		}
+		return c, neg, 0
+	}
+	var q uint64
+	half := -1
+	if k := -d.Exponent; k < int32(len(pow10Uint64)) {
+		p := pow10Uint64[k]
+		var rem uint64
+		q, rem = c/p, c%p
+		if rem == 0 {
+			return q, neg, 0
+		}
+		// Compare rem with p/2 without overflowing 2×rem.
+		switch {
+		case rem > p-rem:
+			half = 1
+		case rem == p-rem:
+			half = 0
+		}
+	}
+	// Otherwise 10**k is more than twice any uint64, so q is 0 and the
+	// discarded fraction is less than half.
+	var qb BigInt
+	qb.SetUint64(q)
+	if r.ShouldAddOne(&qb, neg, half) {
+		if q == math.MaxUint64 {
+			return 0, false, InvalidOperation
+		}
+		q++
+	}
+	return q, neg, Inexact | Rounded
+}
+
+// roundBigInt sets z to d rounded with r to an integer. It returns
+// InvalidOperation and sets z to 0 if d is not finite. An error is returned if
+// the integer has more than maxExactScale digits beyond the coefficient.
+func (d *Decimal) roundBigInt(r Rounder, z *BigInt) (Condition, error) {
+	if d.Form != Finite {
+		z.SetInt64(0)
+		return InvalidOperation, nil
+	}
+	var res Condition
+	var tmp BigInt
+	switch {
+	case d.Coeff.Sign() == 0:
+		z.SetInt64(0)
+	case d.Exponent > maxExactScale:
+		return 0, fmt.Errorf("%s: %s", d.String(), errExponentOutOfRangeStr)
+	case d.Exponent >= 0:
+		z.Mul(&d.Coeff, tableExp10(int64(d.Exponent), &tmp))
+	default:
+		k := -int64(d.Exponent)
+		half := -1
+		if nd := d.NumDigits(); nd < k {
+			// The coefficient is less than 10**(k-1), so the discarded
+			// fraction is less than half.
+			z.SetInt64(0)
+		} else {
+			var rem BigInt
+			p := tableExp10(k, &tmp)
+			z.QuoRem(&d.Coeff, p, &rem)
+			if rem.Sign() == 0 {
+				break
+			}
+			rem.Lsh(&rem, 1)
+			half = rem.Cmp(p)
+		}
+		res = Inexact | Rounded
+		if r.ShouldAddOne(z, d.Negative, half) {
+			z.Add(z, bigOne)
+		}
+	}
+	if d.Negative && z.Sign() != 0 {
+		z.Neg(z)
+	}
+	return res, nil
+}
+
+// pow10Uint64 holds the powers of ten that fit in a uint64.
+var pow10Uint64 = [...]uint64{
+	1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13,
+	1e14, 1e15, 1e16, 1e17, 1e18, 1e19,
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSetUint64(t *testing.T) {
+	for _, x := range []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		d := New(-5, 3)
+		d.SetUint64(x)
+		v, err := d.Uint64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != x || d.Exponent != 0 || d.Negative {
+			t.Errorf("%d: got %s", x, d)
+		}
+	}
+}
+
+func TestDecimalUint64(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected uint64
+		err      string
+	}{
+		{s: "0", expected: 0},
+		{s: "-0", expected: 0},
+		{s: "1.000", expected: 1},
+		{s: "18446744073709551615", expected: math.MaxUint64},
+		{s: "1844674407370955161.5E1", expected: math.MaxUint64},
+		{s: "18446744073709551616", err: "18446744073709551616: greater than max uint64"},
+		{s: "1E+20", err: "1E+20: greater than max uint64"},
+		{s: "1.5", err: "1.5: has fractional part"},
+		{s: "-1", err: "-1: less than zero"},
+		{s: "NaN", err: "NaN is not finite"},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		v, err := d.Uint64()
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("%s: expected error %q, got %v", tc.s, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if v != tc.expected {
+			t.Errorf("%s: expected %d, got %d", tc.s, tc.expected, v)
+		}
+	}
+}
+
+func TestContextInt(t *testing.T) {
+	const inexact = Inexact | Rounded
+	tests := []struct {
+		s   string
+		r   Rounder
+		i64 int64
+		u64 uint64
+		i32 int32
+		u32 uint32
+		// res is the condition of the conversions that are in range.
+		res Condition
+		// oor lists the out of range conversions: "i64", "u64", "i32", "u32".
+		oor string
+	}{
+		{s: "0", i64: 0},
+		{s: "-0.4", res: inexact},
+		{s: "1.5", i64: 2, u64: 2, i32: 2, u32: 2, res: inexact},
+		{s: "2.5", r: RoundHalfEven, i64: 2, u64: 2, i32: 2, u32: 2, res: inexact},
+		{s: "2.5", r: RoundHalfDown, i64: 2, u64: 2, i32: 2, u32: 2, res: inexact},
+		{s: "2.5000000000000000000000001", r: RoundHalfDown, i64: 3, u64: 3, i32: 3, u32: 3, res: inexact},
+		{s: "-1.5", i64: -2, i32: -2, res: inexact, oor: "u64 u32"},
+		{s: "-1.5", r: RoundDown, i64: -1, i32: -1, res: inexact, oor: "u64 u32"},
+		{s: "-1.5", r: RoundCeiling, i64: -1, i32: -1, res: inexact, oor: "u64 u32"},
+		{s: "-0.5", r: RoundCeiling, res: inexact},
+		{s: "0.1", r: RoundUp, i64: 1, u64: 1, i32: 1, u32: 1, res: inexact},
+		{s: "1E-40", r: RoundUp, i64: 1, u64: 1, i32: 1, u32: 1, res: inexact},
+		{s: "1E-40", r: RoundFloor, res: inexact},
+		{s: "-1E-40", r: RoundFloor, i64: -1, i32: -1, res: inexact, oor: "u64 u32"},
+		{s: "12345678901234567890123E-4", i64: 1234567890123456789, u64: 1234567890123456789, res: inexact, oor: "i32 u32"},
+		{s: "2147483647.4", i64: math.MaxInt32, u64: math.MaxInt32, i32: math.MaxInt32, u32: math.MaxInt32, res: inexact},
+		{s: "2147483647.5", i64: 1 << 31, u64: 1 << 31, u32: 1 << 31, res: inexact, oor: "i32"},
+		{s: "-2147483648.5", r: RoundDown, i64: math.MinInt32, i32: math.MinInt32, res: inexact, oor: "u64 u32"},
+		{s: "4294967295", i64: math.MaxUint32, u64: math.MaxUint32, u32: math.MaxUint32, oor: "i32"},
+		{s: "9223372036854775807", i64: math.MaxInt64, u64: math.MaxInt64, oor: "i32 u32"},
+		{s: "-9223372036854775808", i64: math.MinInt64, oor: "u64 i32 u32"},
+		{s: "-9223372036854775808.5", r: RoundDown, i64: math.MinInt64, res: inexact, oor: "u64 i32 u32"},
+		{s: "-9223372036854775808.5", oor: "i64 u64 i32 u32"},
+		{s: "9223372036854775808", u64: 1 << 63, oor: "i64 i32 u32"},
+		{s: "18446744073709551615.4", u64: math.MaxUint64, res: inexact, oor: "i64 i32 u32"},
+		{s: "18446744073709551615.5", oor: "i64 u64 i32 u32"},
+		{s: "1E+19", u64: 1e19, oor: "i64 i32 u32"},
+		{s: "1E+20", oor: "i64 u64 i32 u32"},
+		{s: "1E+100000", oor: "i64 u64 i32 u32"},
+		{s: "0E+100000"},
+		{s: "NaN", oor: "i64 u64 i32 u32"},
+		{s: "-Infinity", oor: "i64 u64 i32 u32"},
+	}
+	for _, tc := range tests {
+		c := Context{Rounding: tc.r}
+		d := newDecimal(t, testCtx, tc.s)
+		check := func(name string, got, expected interface{}, res Condition, err error) {
+			t.Helper()
+			if err != nil {
+				t.Fatalf("%s %s: %v", tc.s, name, err)
+			}
+			expectedRes := tc.res
+			if strings.Contains(tc.oor, name) {
+				expected = fmt.Sprint(0)
+				expectedRes = InvalidOperation
+			}
+			if fmt.Sprint(got) != fmt.Sprint(expected) || res != expectedRes {
+				t.Errorf("%s %s %s: expected %v %s, got %v %s", tc.s, tc.r, name, expected, expectedRes, got, res)
+			}
+		}
+		i64, res, err := c.Int64(d)
+		check("i64", i64, tc.i64, res, err)
+		u64, res, err := c.Uint64(d)
+		check("u64", u64, tc.u64, res, err)
+		i32, res, err := c.Int32(d)
+		check("i32", i32, tc.i32, res, err)
+		u32, res, err := c.Uint32(d)
+		check("u32", u32, tc.u32, res, err)
+	}
+
+	if _, _, err := BaseContext.Int64(newDecimal(t, testCtx, "1E+19")); err == nil {
+		t.Fatal("expected out of range error")
+	}
+}
+
+// TestContextBigInt compares conversions with RoundToIntegralExact.
+func TestContextBigInt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(0))
+	inputs := []string{"0", "-0", "0E-50", "0E+50", "1E+50", "-1.5", "2.5", "-2.5", "99.5", "1E-1000", "-123E+5"}
+	for i := 0; i < 2000; i++ {
+		digits := rnd.Intn(40) + 1
+		coeff := make([]byte, digits)
+		for j := range coeff {
+			coeff[j] = byte('0' + rnd.Intn(10))
+		}
+		inputs = append(inputs, fmt.Sprintf("%s%sE%d", []string{"", "-"}[rnd.Intn(2)], coeff, rnd.Intn(50)-40))
+	}
+	for _, s := range inputs {
+		d := newDecimal(t, testCtx, s)
+		for r := range roundings {
+			c := Context{Rounding: r}
+			oc := Context{Precision: 1000, MaxExponent: MaxExponent, MinExponent: MinExponent, Rounding: r}
+			// Quantize rounds values below 0.1 to zero in every mode. All
+			// values in (0, 0.5) round alike, so use 0.1 instead.
+			od := d
+			if !d.IsZero() && d.NumDigits() < -int64(d.Exponent) {
+				od = New(1, -1)
+				od.Negative = d.Negative
+			}
+			var expected Decimal
+			if _, err := oc.RoundToIntegralExact(&expected, od); err != nil {
+				t.Fatal(err)
+			}
+			expectedInt := expected.Coeff.MathBigInt()
+			expectedInt.Mul(expectedInt, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(expected.Exponent)), nil))
+			if expected.Negative {
+				expectedInt.Neg(expectedInt)
+			}
+			expectedRes := Condition(0)
+			if expected.Cmp(d) != 0 {
+				expectedRes = Inexact | Rounded
+			}
+
+			b, res, err := c.BigInt(nil, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.MathBigInt().Cmp(expectedInt) != 0 || res != expectedRes {
+				t.Fatalf("%s %s: expected %s %s, got %s %s", s, r, expectedInt, expectedRes, b, res)
+			}
+			m, res, err := c.MathBigInt(new(big.Int).SetInt64(7), d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if m.Cmp(expectedInt) != 0 || res != expectedRes {
+				t.Fatalf("%s %s: expected %s %s, got %s %s", s, r, expectedInt, expectedRes, m, res)
+			}
+			i64, res, err := c.Int64(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expectedInt.IsInt64() {
+				if i64 != expectedInt.Int64() || res != expectedRes {
+					t.Fatalf("%s %s: expected int64 %s %s, got %d %s", s, r, expectedInt, expectedRes, i64, res)
+				}
+			} else if res != InvalidOperation {
+				t.Fatalf("%s %s: expected out of range, got %d %s", s, r, i64, res)
+			}
+		}
+	}
+
+	if _, res, _ := (&Context{}).BigInt(nil, newDecimal(t, testCtx, "sNaN")); res != InvalidOperation {
+		t.Fatalf("expected invalid operation, got %s", res)
+	}
+	// Integers with too many digits are an error rather than a power of ten
+	// that takes very long to compute.
+	huge := newDecimal(t, testCtx, "-1E+900000000")
+	if _, _, err := (&Context{}).BigInt(nil, huge); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, _, err := (&Context{}).MathBigInt(nil, huge); err == nil {
+		t.Fatal("expected error")
+	}
+	if v, res, _ := (&Context{}).Int64(huge); v != 0 || res != InvalidOperation {
+		t.Fatalf("expected invalid operation, got %d %s", v, res)
+	}
+	if b, res, err := (&Context{}).BigInt(nil, newDecimal(t, testCtx, "0E+900000000")); err != nil || b.Sign() != 0 || res != 0 {
+		t.Fatalf("expected 0, got %s %s %v", b, res, err)
+	}
+}
+
+func TestContextIntAllocs(t *testing.T) {
+	c := Context{Rounding: RoundHalfEven}
+	for _, s := range []string{"12", "-1.5", "123456.789", "1E-30"} {
+		d := newDecimal(t, testCtx, s)
+		allocs := testing.AllocsPerRun(100, func() {
+			if _, _, err := c.Int64(d); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%s: expected no allocations, got %.1f", s, allocs)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON implements the json.Marshaler interface. d is encoded as a JSON
+// string, like MarshalText. To encode d as a JSON number, use JSONNumber.
+func (d *Decimal) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+	buf := append(make([]byte, 0, 16), '"')
+	buf = d.Append(buf, 'G')
+	return append(buf, '"'), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It accepts JSON
+// numbers and JSON strings containing any value accepted by SetString,
+// including NaN and Infinity. A JSON null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		if bytes.IndexByte(data, '\\') < 0 && len(data) >= 2 && data[len(data)-1] == '"' {
+			_, _, err := d.SetStringBytes(data[1 : len(data)-1])
+			return err
+		}
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		_, _, err := d.SetString(s)
+		return err
+	}
+	if len(data) == 0 || (data[0] != '-' && (data[0] < '0' || data[0] > '9')) {
+		return fmt.Errorf("cannot unmarshal JSON %q into a Decimal", data)
+	}
+	_, _, err := d.SetStringBytes(data)
+	return err
+}
+
+// SetJSONNumber sets d to the value of n, as produced by a json.Decoder with
+// UseNumber, and returns d.
+func (d *Decimal) SetJSONNumber(n json.Number) (*Decimal, error) {
+	if _, _, err := d.SetString(string(n)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// JSONNumber is a Decimal that is encoded as a JSON number instead of a JSON
+// string, without loss of precision. Since JSON numbers can't represent them,
+// NaNs and infinities are encoded as the JSON strings "NaN", "sNaN",
+// "Infinity" and "-Infinity". Decoding accepts the same inputs as
+// (*Decimal).UnmarshalJSON.
+type JSONNumber struct {
+	Decimal
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n JSONNumber) MarshalJSON() ([]byte, error) {
+	if n.Form != Finite {
+		return n.Decimal.MarshalJSON()
+	}
+	// The 'G' format of a finite Decimal is always a valid JSON number.
+	return n.Append(make([]byte, 0, 16), 'G'), nil
+}
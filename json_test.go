@@ -0,0 +1,156 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONMarshal(t *testing.T) {
+	tests := []struct {
+		s      string
+		str    string
+		number string
+	}{
+		{s: "0", str: `"0"`, number: `0`},
+		{s: "-0.00", str: `"-0.00"`, number: `-0.00`},
+		{s: "123.456", str: `"123.456"`, number: `123.456`},
+		{s: "1.2E+3", str: `"1.2E+3"`, number: `1.2E+3`},
+		{s: "1E-10", str: `"1E-10"`, number: `1E-10`},
+		{s: "0E-7", str: `"0.0000000"`, number: `0.0000000`},
+		{s: "0E+3", str: `"0E+3"`, number: `0E+3`},
+		{
+			s:      "298472983472983471903246121093472394872319615612417471234712061",
+			str:    `"298472983472983471903246121093472394872319615612417471234712061"`,
+			number: `298472983472983471903246121093472394872319615612417471234712061`,
+		},
+		{s: "NaN", str: `"NaN"`, number: `"NaN"`},
+		{s: "sNaN", str: `"sNaN"`, number: `"sNaN"`},
+		{s: "Inf", str: `"Infinity"`, number: `"Infinity"`},
+		{s: "-Inf", str: `"-Infinity"`, number: `"-Infinity"`},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tc.str {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.str, b)
+		}
+		b, err = json.Marshal(JSONNumber{*d})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tc.number {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.number, b)
+		}
+		if !json.Valid(b) {
+			t.Fatalf("%s: invalid JSON %s", tc.s, b)
+		}
+		var n JSONNumber
+		if err := json.Unmarshal(b, &n); err != nil {
+			t.Fatal(err)
+		}
+		if n.String() != d.String() {
+			t.Fatalf("%s: expected %s, got %s", tc.s, d, &n.Decimal)
+		}
+	}
+}
+
+func TestJSONNumberStruct(t *testing.T) {
+	type row struct {
+		Price JSONNumber
+		Ptr   *JSONNumber
+		Str   *Decimal
+	}
+	in := row{
+		Price: JSONNumber{*New(1999, -2)},
+		Ptr:   &JSONNumber{*New(-5, 3)},
+		Str:   New(1, -1),
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"Price":19.99,"Ptr":-5E+3,"Str":"0.1"}`
+	if string(b) != expected {
+		t.Fatalf("expected %s, got %s", expected, b)
+	}
+	var out row
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Price.Cmp(&in.Price.Decimal) != 0 || out.Ptr.Cmp(&in.Ptr.Decimal) != 0 || out.Str.Cmp(in.Str) != 0 {
+		t.Fatalf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestJSONUnmarshal(t *testing.T) {
+	tests := []struct {
+		json string
+		s    string
+	}{
+		{json: `1.5`, s: "1.5"},
+		{json: `"1.5"`, s: "1.5"},
+		{json: ` -12E-2 `, s: "-0.12"},
+		{json: `"1.5"`, s: "1.5"},
+		{json: `"Infinity"`, s: "Infinity"},
+		{json: `"-inf"`, s: "-Infinity"},
+		{json: `"nan"`, s: "NaN"},
+		{json: `123456789012345678901234567890.123456789`, s: "123456789012345678901234567890.123456789"},
+		{json: `null`, s: "7"},
+		{json: `true`},
+		{json: `"abc"`},
+		{json: `{}`},
+		{json: `"1.5`},
+	}
+	for _, tc := range tests {
+		d := New(7, 0)
+		err := d.UnmarshalJSON([]byte(tc.json))
+		if tc.s == "" {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %s", tc.json, d)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.json, err)
+		}
+		if d.String() != tc.s {
+			t.Fatalf("%s: expected %s, got %s", tc.json, tc.s, d)
+		}
+	}
+
+	// Numbers decoded with UseNumber.
+	dec := json.NewDecoder(bytes.NewReader([]byte(`{"a": 1.10, "b": -3e2}`)))
+	dec.UseNumber()
+	var m map[string]interface{}
+	if err := dec.Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+	var d Decimal
+	if _, err := d.SetJSONNumber(m["a"].(json.Number)); err != nil || d.String() != "1.10" {
+		t.Fatalf("unexpected: %s, %v", &d, err)
+	}
+	if _, err := d.SetJSONNumber(m["b"].(json.Number)); err != nil || d.String() != "-3E+2" {
+		t.Fatalf("unexpected: %s, %v", &d, err)
+	}
+	if _, err := d.SetJSONNumber("x"); err == nil {
+		t.Fatal("expected error")
+	}
+}
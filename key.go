@@ -0,0 +1,408 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Key encoding
+//
+// A key is an encoding of a Decimal whose bytes sort, under bytes.Compare, in
+// the same order as the Decimals under Cmp. Keys are prefix-free, so they can
+// be concatenated with other keys to form composite keys.
+//
+// A finite, non-zero value is written as 0.M × 10**E, where the mantissa M has
+// no leading or trailing zeros. The key is a marker byte that encodes the sign
+// and the magnitude class of E, followed by E itself when it does not fit in
+// the marker, followed by the digits of M in pairs. Each pair of digits p is
+// written as the byte 2p+1, except for the last one which is written as 2p,
+// with a zero appended if M has an odd number of digits. A shorter mantissa
+// thus sorts before a longer one sharing its prefix, and the end of the key is
+// known without a length. The bytes of negative values are complemented.
+//
+// Zero has its own marker, and trailing zeros of the coefficient are not
+// encoded, so numerically equal values such as 1.0 and 1.00 have identical
+// keys. NaN sorts before -Infinity, which sorts before all finite values,
+// followed by +Infinity. Descending keys are the complement of the ascending
+// ones.
+//
+// The lossless variants append the exponent of the Decimal to the key of
+// finite values and preserve the sign of zeros and the kind of NaN. Equal
+// values then sort by exponent.
+const (
+	keyNaN       = 0x01
+	keyNegInf    = 0x02
+	keyNegLarge  = 0x03
+	keyNegMedium = 0x04 // + 10 - E, for 0 <= E <= 10
+	keyNegSmall  = 0x0f
+	keyNegZero   = 0x10 // lossless only
+	keyZero      = 0x11
+	keyPosSmall  = 0x12
+	keyPosMedium = 0x13 // + E, for 0 <= E <= 10
+	keyPosLarge  = 0x1e
+	keyInf       = 0x1f
+
+	keyMediumMax = 10
+
+	keyNaNNegative  = 0x01
+	keyNaNSignaling = 0x02
+)
+
+var errKeyShort = errors.New("key too short")
+
+// AppendKeyAscending appends to buf the ascending key encoding of d and
+// returns the extended buffer. Numerically equal values have identical keys.
+func (d *Decimal) AppendKeyAscending(buf []byte) []byte {
+	return d.appendKey(buf, false)
+}
+
+// AppendKeyDescending appends to buf the descending key encoding of d, which
+// sorts in the reverse order of the ascending encoding, and returns the
+// extended buffer.
+func (d *Decimal) AppendKeyDescending(buf []byte) []byte {
+	return invertKey(d.appendKey(buf, false), len(buf))
+}
+
+// AppendLosslessKeyAscending is like AppendKeyAscending, but preserves the
+// exponent of d, the sign of zeros, and the kind and sign of NaNs.
+func (d *Decimal) AppendLosslessKeyAscending(buf []byte) []byte {
+	return d.appendKey(buf, true)
+}
+
+// AppendLosslessKeyDescending is like AppendKeyDescending, but preserves the
+// exponent of d, the sign of zeros, and the kind and sign of NaNs.
+func (d *Decimal) AppendLosslessKeyDescending(buf []byte) []byte {
+	return invertKey(d.appendKey(buf, true), len(buf))
+}
+
+// DecodeKeyAscending sets d to the value of the ascending key at the start of
+// key and returns the remainder of key. Since the key does not record the
+// exponent, d has no trailing zeros.
+func (d *Decimal) DecodeKeyAscending(key []byte) ([]byte, error) {
+	return d.decodeKey(key, false, false)
+}
+
+// DecodeKeyDescending sets d to the value of the descending key at the start
+// of key and returns the remainder of key.
+func (d *Decimal) DecodeKeyDescending(key []byte) ([]byte, error) {
+	return d.decodeKey(key, true, false)
+}
+
+// DecodeLosslessKeyAscending sets d to the value of the lossless ascending
+// key at the start of key and returns the remainder of key.
+func (d *Decimal) DecodeLosslessKeyAscending(key []byte) ([]byte, error) {
+	return d.decodeKey(key, false, true)
+}
+
+// DecodeLosslessKeyDescending sets d to the value of the lossless descending
+// key at the start of key and returns the remainder of key.
+func (d *Decimal) DecodeLosslessKeyDescending(key []byte) ([]byte, error) {
+	return d.decodeKey(key, true, true)
+}
+
+func (d *Decimal) appendKey(buf []byte, lossless bool) []byte {
+	switch d.Form {
+	case NaN, NaNSignaling:
+		buf = append(buf, keyNaN)
+		if lossless {
+			var flags byte
+			if d.Negative {
+				flags |= keyNaNNegative
+			}
+			if d.Form == NaNSignaling {
+				flags |= keyNaNSignaling
+			}
+			buf = append(buf, flags)
+		}
+		return buf
+	case Infinite:
+		if d.Negative {
+			return append(buf, keyNegInf)
+		}
+		return append(buf, keyInf)
+	}
+	if d.Coeff.Sign() == 0 {
+		if !lossless {
+			return append(buf, keyZero)
+		}
+		if d.Negative {
+			buf = append(buf, keyNegZero)
+		} else {
+			buf = append(buf, keyZero)
+		}
+		return appendKeyExponent(buf, d.Exponent)
+	}
+
+	var scratch [40]byte
+	digits := d.Coeff.Append(scratch[:0], 10)
+	// The value is 0.digits × 10**e.
+	e := int64(d.Exponent) + int64(len(digits))
+	for digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+
+	start := len(buf)
+	switch {
+	case e < 0:
+		buf = append(buf, keyPosSmall)
+		buf = invertKey(appendKeyUvarint(buf, uint64(-e)), start+1)
+	case e <= keyMediumMax:
+		buf = append(buf, keyPosMedium+byte(e))
+	default:
+		buf = append(buf, keyPosLarge)
+		buf = appendKeyUvarint(buf, uint64(e))
+	}
+	for i := 0; i < len(digits); i += 2 {
+		p := (digits[i] - '0') * 10
+		if i+1 < len(digits) {
+			p += digits[i+1] - '0'
+		}
+		if i+2 < len(digits) {
+			buf = append(buf, 2*p+1)
+		} else {
+			buf = append(buf, 2*p)
+		}
+	}
+	if d.Negative {
+		// Reverse the order of everything but the marker, and map the
+		// positive markers to their negative counterparts.
+		invertKey(buf, start+1)
+		switch m := buf[start]; {
+		case m == keyPosSmall:
+			buf[start] = keyNegSmall
+		case m == keyPosLarge:
+			buf[start] = keyNegLarge
+		default:
+			buf[start] = keyNegMedium + keyMediumMax - (m - keyPosMedium)
+		}
+	}
+	if lossless {
+		buf = appendKeyExponent(buf, d.Exponent)
+	}
+	return buf
+}
+
+func (d *Decimal) decodeKey(key []byte, desc, lossless bool) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errKeyShort
+	}
+	// b returns the i'th byte of key in ascending form.
+	b := func(i int) byte {
+		if desc {
+			return ^key[i]
+		}
+		return key[i]
+	}
+	m := b(0)
+	var neg bool
+	var e int64
+	n := 1
+	switch {
+	case m == keyNaN:
+		d.Form = NaN
+		d.Negative = false
+		d.Exponent = 0
+		d.Coeff.SetInt64(0)
+		if !lossless {
+			return key[1:], nil
+		}
+		if len(key) < 2 {
+			return nil, errKeyShort
+		}
+		flags := b(1)
+		if flags&^(keyNaNNegative|keyNaNSignaling) != 0 {
+			return nil, fmt.Errorf("invalid NaN key flags %#x", flags)
+		}
+		d.Negative = flags&keyNaNNegative != 0
+		if flags&keyNaNSignaling != 0 {
+			d.Form = NaNSignaling
+		}
+		return key[2:], nil
+	case m == keyNegInf || m == keyInf:
+		d.Form = Infinite
+		d.Negative = m == keyNegInf
+		d.Exponent = 0
+		d.Coeff.SetInt64(0)
+		return key[1:], nil
+	case m == keyZero || (m == keyNegZero && lossless):
+		d.Form = Finite
+		d.Negative = m == keyNegZero
+		d.Exponent = 0
+		d.Coeff.SetInt64(0)
+		if !lossless {
+			return key[1:], nil
+		}
+		exp, err := decodeKeyExponent(key[1:], desc)
+		if err != nil {
+			return nil, err
+		}
+		d.Exponent = exp
+		return key[5:], nil
+	case m == keyNegLarge || m == keyPosLarge:
+		neg = m == keyNegLarge
+		u, w, err := decodeKeyUvarint(key[1:], desc != neg)
+		if err != nil {
+			return nil, err
+		}
+		e = int64(u)
+		n += w
+	case m == keyNegSmall || m == keyPosSmall:
+		neg = m == keyNegSmall
+		u, w, err := decodeKeyUvarint(key[1:], desc == neg)
+		if err != nil {
+			return nil, err
+		}
+		e = -int64(u)
+		n += w
+	case m >= keyNegMedium && m <= keyNegMedium+keyMediumMax:
+		neg = true
+		e = int64(keyNegMedium + keyMediumMax - m)
+	case m >= keyPosMedium && m <= keyPosMedium+keyMediumMax:
+		e = int64(m - keyPosMedium)
+	default:
+		return nil, fmt.Errorf("invalid key marker %#x", m)
+	}
+
+	// Decode the digit pairs of the mantissa.
+	var scratch [40]byte
+	digits := scratch[:0]
+	for {
+		if n >= len(key) {
+			return nil, errKeyShort
+		}
+		p := b(n)
+		if neg {
+			p = ^p
+		}
+		n++
+		if p >= 200 {
+			return nil, fmt.Errorf("invalid key mantissa byte %#x", p)
+		}
+		digits = append(digits, '0'+p/2/10, '0'+p/2%10)
+		if p%2 == 0 {
+			break
+		}
+	}
+	if digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+	if digits[0] == '0' || digits[len(digits)-1] == '0' {
+		return nil, errors.New("invalid key mantissa")
+	}
+	e -= int64(len(digits))
+	if e < -1<<31 || e > 1<<31-1 {
+		return nil, fmt.Errorf("key exponent %d out of range", e)
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = int32(e)
+	if _, ok := d.Coeff.SetString(string(digits), 10); !ok {
+		return nil, errors.New("invalid key mantissa")
+	}
+	if !lossless {
+		return key[n:], nil
+	}
+	exp, err := decodeKeyExponent(key[n:], desc)
+	if err != nil {
+		return nil, err
+	}
+	if exp > d.Exponent {
+		return nil, fmt.Errorf("key exponent %d greater than %d", exp, d.Exponent)
+	}
+	if int64(d.Exponent)-int64(exp) > maxExactScale {
+		// Keys can come from untrusted input, so don't allocate the
+		// trailing zeros of a crafted exponent.
+		return nil, fmt.Errorf("key exponent %d: %s", exp, errExponentOutOfRangeStr)
+	}
+	if exp < d.Exponent {
+		var tmpE BigInt
+		d.Coeff.Mul(&d.Coeff, tableExp10(int64(d.Exponent)-int64(exp), &tmpE))
+		d.Exponent = exp
+	}
+	return key[n+4:], nil
+}
+
+// invertKey complements the bytes of buf from start, and returns buf.
+func invertKey(buf []byte, start int) []byte {
+	for i := start; i < len(buf); i++ {
+		buf[i] = ^buf[i]
+	}
+	return buf
+}
+
+// appendKeyUvarint appends an order-preserving encoding of u to buf: the
+// number of significant bytes of u followed by those bytes in big-endian
+// order.
+func appendKeyUvarint(buf []byte, u uint64) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], u)
+	i := 0
+	for i < len(tmp) && tmp[i] == 0 {
+		i++
+	}
+	buf = append(buf, byte(len(tmp)-i))
+	return append(buf, tmp[i:]...)
+}
+
+// decodeKeyUvarint decodes a uint64 encoded by appendKeyUvarint, complemented
+// if inverted is set. It returns the value and the number of bytes read.
+func decodeKeyUvarint(key []byte, inverted bool) (uint64, int, error) {
+	if len(key) == 0 {
+		return 0, 0, errKeyShort
+	}
+	var mask byte
+	if inverted {
+		mask = 0xff
+	}
+	l := int(key[0] ^ mask)
+	if l > 8 {
+		return 0, 0, fmt.Errorf("invalid key varint length %d", l)
+	}
+	if len(key) < 1+l {
+		return 0, 0, errKeyShort
+	}
+	// Exponents of keys are far smaller than 1<<63, so reject larger values
+	// to avoid overflowing the conversion to int64.
+	if l == 8 && key[1]^mask >= 0x80 {
+		return 0, 0, errors.New("key exponent out of range")
+	}
+	var u uint64
+	for _, c := range key[1 : 1+l] {
+		u = u<<8 | uint64(c^mask)
+	}
+	return u, 1 + l, nil
+}
+
+// appendKeyExponent appends the exponent of a lossless key to buf.
+func appendKeyExponent(buf []byte, exp int32) []byte {
+	var tmp [4]byte
+	binary.BigEndian.PutUint32(tmp[:], uint32(exp)^(1<<31))
+	return append(buf, tmp[:]...)
+}
+
+// decodeKeyExponent decodes an exponent encoded by appendKeyExponent.
+func decodeKeyExponent(key []byte, desc bool) (int32, error) {
+	if len(key) < 4 {
+		return 0, errKeyShort
+	}
+	u := binary.BigEndian.Uint32(key)
+	if desc {
+		u = ^u
+	}
+	return int32(u ^ (1 << 31)), nil
+}
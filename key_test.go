@@ -0,0 +1,270 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestKeyEncoding(t *testing.T) {
+	tests := []struct {
+		s   string
+		hex string
+	}{
+		{s: "NaN", hex: "01"},
+		{s: "-NaN", hex: "01"},
+		{s: "sNaN", hex: "01"},
+		{s: "-Inf", hex: "02"},
+		{s: "-1E+11", hex: "03fef3eb"},
+		{s: "-123.45", hex: "0be6ba9b"},
+		{s: "-1", hex: "0deb"},
+		{s: "-0.01", hex: "0f0101eb"},
+		{s: "-0", hex: "11"},
+		{s: "0", hex: "11"},
+		{s: "0E+5", hex: "11"},
+		{s: "0.01", hex: "12fefe14"},
+		{s: "0.1", hex: "1314"},
+		{s: "1", hex: "1414"},
+		{s: "1.0", hex: "1414"},
+		{s: "1.00", hex: "1414"},
+		{s: "10", hex: "1514"},
+		{s: "12", hex: "1518"},
+		{s: "123", hex: "16193c"},
+		{s: "123.45", hex: "16194564"},
+		{s: "1E+9", hex: "1d14"},
+		{s: "1E+10", hex: "1e010b14"},
+		{s: "Inf", hex: "1f"},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		asc := d.AppendKeyAscending(nil)
+		if h := hex.EncodeToString(asc); h != tc.hex {
+			t.Errorf("%s: expected %s, got %s", tc.s, tc.hex, h)
+		}
+		desc := d.AppendKeyDescending(nil)
+		for i := range desc {
+			if desc[i] != ^asc[i] {
+				t.Fatalf("%s: descending key %x is not the complement of %x", tc.s, desc, asc)
+			}
+		}
+		var r Decimal
+		rest, err := r.DecodeKeyAscending(asc)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if len(rest) != 0 {
+			t.Fatalf("%s: unexpected remainder %x", tc.s, rest)
+		}
+		if d.Form == Finite && r.Cmp(d) != 0 || d.Form != r.Form && !keyIsNaN(d) {
+			t.Fatalf("%s: decoded %s", tc.s, &r)
+		}
+	}
+}
+
+// randKeyDecimal returns a random Decimal for key encoding tests, including
+// special values and values with trailing zeros.
+func randKeyDecimal(rng *rand.Rand) *Decimal {
+	switch rng.Intn(20) {
+	case 0:
+		return &Decimal{Form: Infinite, Negative: rng.Intn(2) == 0}
+	case 1:
+		return &Decimal{Form: NaN, Negative: rng.Intn(2) == 0}
+	case 2:
+		return &Decimal{Form: NaNSignaling}
+	case 3:
+		return &Decimal{Negative: rng.Intn(2) == 0, Exponent: int32(rng.Intn(20) - 10)}
+	}
+	var buf bytes.Buffer
+	if rng.Intn(2) == 0 {
+		buf.WriteByte('-')
+	}
+	buf.WriteByte(byte('1' + rng.Intn(9)))
+	n := rng.Intn(6)
+	if rng.Intn(10) == 0 {
+		n = rng.Intn(60)
+	}
+	for i := 0; i < n; i++ {
+		buf.WriteByte(byte('0' + rng.Intn(10)))
+	}
+	for i := rng.Intn(4); i > 0; i-- {
+		buf.WriteByte('0')
+	}
+	exp := rng.Intn(30) - 15
+	if rng.Intn(10) == 0 {
+		exp = rng.Intn(2000) - 1000
+	}
+	fmt.Fprintf(&buf, "E%d", exp)
+	d, _, err := NewFromString(buf.String())
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func keyIsNaN(d *Decimal) bool {
+	return d.Form == NaN || d.Form == NaNSignaling
+}
+
+// keyCmp compares x and y as ordered by keys: NaNs first, then by Cmp.
+func keyCmp(x, y *Decimal) int {
+	switch xn, yn := keyIsNaN(x), keyIsNaN(y); {
+	case xn && yn:
+		return 0
+	case xn:
+		return -1
+	case yn:
+		return 1
+	}
+	return x.Cmp(y)
+}
+
+func TestKeyOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < 20000; i++ {
+		x, y := randKeyDecimal(rng), randKeyDecimal(rng)
+		if i%5 == 0 && x.Form == Finite {
+			// Equal values with different exponents.
+			y.Set(x)
+			y.Coeff.Mul(&y.Coeff, bigTen)
+			y.Exponent--
+		}
+		c := keyCmp(x, y)
+		xa, ya := x.AppendKeyAscending(nil), y.AppendKeyAscending(nil)
+		if k := bytes.Compare(xa, ya); k != c {
+			t.Fatalf("%s, %s: expected %d, got %d (%x, %x)", x, y, c, k, xa, ya)
+		}
+		xd, yd := x.AppendKeyDescending(nil), y.AppendKeyDescending(nil)
+		if k := bytes.Compare(xd, yd); k != -c {
+			t.Fatalf("%s, %s: expected %d, got %d (%x, %x)", x, y, -c, k, xd, yd)
+		}
+		// Lossless keys are only equal for identical Decimals, but must be
+		// ordered by value.
+		xl, yl := x.AppendLosslessKeyAscending(nil), y.AppendLosslessKeyAscending(nil)
+		if k := bytes.Compare(xl, yl); c != 0 && k != c {
+			t.Fatalf("%s, %s: expected %d, got %d (%x, %x)", x, y, c, k, xl, yl)
+		}
+		xl, yl = x.AppendLosslessKeyDescending(nil), y.AppendLosslessKeyDescending(nil)
+		if k := bytes.Compare(xl, yl); c != 0 && k != -c {
+			t.Fatalf("%s, %s: expected %d, got %d (%x, %x)", x, y, -c, k, xl, yl)
+		}
+	}
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	type codec struct {
+		name   string
+		encode func(d *Decimal, buf []byte) []byte
+		decode func(d *Decimal, key []byte) ([]byte, error)
+	}
+	codecs := []codec{
+		{"asc", (*Decimal).AppendKeyAscending, (*Decimal).DecodeKeyAscending},
+		{"desc", (*Decimal).AppendKeyDescending, (*Decimal).DecodeKeyDescending},
+		{"lossless asc", (*Decimal).AppendLosslessKeyAscending, (*Decimal).DecodeLosslessKeyAscending},
+		{"lossless desc", (*Decimal).AppendLosslessKeyDescending, (*Decimal).DecodeLosslessKeyDescending},
+	}
+	for i := 0; i < 5000; i++ {
+		x := randKeyDecimal(rng)
+		for _, c := range codecs {
+			// Encode a composite key to verify that keys are self-delimiting.
+			key := c.encode(x, []byte("prefix"))
+			key = c.encode(New(7, 0), key)
+			var r Decimal
+			rest, err := c.decode(&r, key[len("prefix"):])
+			if err != nil {
+				t.Fatalf("%s %s: %v", c.name, x, err)
+			}
+			lossless := c.name[0] == 'l'
+			switch {
+			case lossless:
+				if r.String() != x.String() || r.Negative != x.Negative {
+					t.Fatalf("%s %s: decoded %s", c.name, x, &r)
+				}
+			case keyIsNaN(x):
+				if r.Form != NaN {
+					t.Fatalf("%s %s: decoded %s", c.name, x, &r)
+				}
+			case x.Form == Infinite:
+				if r.CmpTotal(x) != 0 {
+					t.Fatalf("%s %s: decoded %s", c.name, x, &r)
+				}
+			default:
+				var reduced Decimal
+				reduced.Reduce(x)
+				if r.Cmp(x) != 0 || (!r.IsZero() && r.CmpTotal(&reduced) != 0) {
+					t.Fatalf("%s %s: decoded %s", c.name, x, &r)
+				}
+			}
+			if _, err := r.DecodeKeyAscending(nil); err == nil {
+				t.Fatal("expected error")
+			}
+			if rest, err = c.decode(&r, rest); err != nil || len(rest) != 0 || r.String() != "7" {
+				t.Fatalf("%s %s: unexpected remainder: %s, %x, %v", c.name, x, &r, rest, err)
+			}
+		}
+	}
+}
+
+func TestKeyDecodeErrors(t *testing.T) {
+	tests := []struct {
+		hex      string
+		lossless bool
+	}{
+		{hex: ""},
+		{hex: "00"},
+		{hex: "20"},
+		{hex: "10"},
+		{hex: "14"},
+		{hex: "1415"},
+		{hex: "14c8"},
+		{hex: "1400"},
+		{hex: "1e09"},
+		{hex: "1e08"},
+		{hex: "1e0201"},
+		{hex: "1e08ffffffffffffffff14"},
+		{hex: "01", lossless: true},
+		{hex: "0104", lossless: true},
+		{hex: "1180", lossless: true},
+		{hex: "141480000001", lossless: true},
+		// Exponents that would scale the coefficient by too many digits.
+		{hex: "14147ffe795f", lossless: true},
+		{hex: "141400000000", lossless: true},
+	}
+	for _, tc := range tests {
+		b, err := hex.DecodeString(tc.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Decimal
+		decode := d.DecodeKeyAscending
+		if tc.lossless {
+			decode = d.DecodeLosslessKeyAscending
+		}
+		if _, err := decode(b); err == nil {
+			t.Errorf("%s: expected error, got %s", tc.hex, &d)
+		}
+	}
+
+	// The largest scale is accepted.
+	b, _ := hex.DecodeString("14147ffe7960")
+	var d Decimal
+	if _, err := d.DecodeLosslessKeyAscending(b); err != nil || d.Exponent != -maxExactScale || d.NumDigits() != maxExactScale+1 {
+		t.Errorf("unexpected %v", err)
+	}
+}
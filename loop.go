@@ -16,10 +16,10 @@ type loop struct {
 	name          string // The name of the function we are evaluating.
 	i             uint64 // Loop count.
 	precision     int32
-	maxIterations uint64   // When to give up.
-	arg           *Decimal // original argument to function; only used for diagnostic.
-	prevZ         Decimal  // Result from the previous iteration.
-	delta         Decimal  // |Change| from previous iteration.
+	maxIterations uint64  // When to give up.
+	arg           Decimal // original argument to function; only used for diagnostic.
+	prevZ         Decimal // Result from the previous iteration.
+	delta         Decimal // |Change| from previous iteration.
 }
 
 const digitsToBitsRatio = math.Ln10 / math.Ln2
@@ -34,13 +34,21 @@ const digitsToBitsRatio = math.Ln10 / math.Ln2
 // 	 - maxItersPerDigit: after this many iterations per digit of precision, the
 // 	                     loop ends in error.
 func (c *Context) newLoop(name string, arg *Decimal, precision uint32, maxItersPerDigit int) *loop {
-	return &loop{
-		c:             c,
-		name:          name,
-		arg:           new(Decimal).Set(arg),
-		precision:     int32(precision),
-		maxIterations: 10 + uint64(maxItersPerDigit*int(precision)),
-	}
+	return c.initLoop(new(loop), name, arg, precision, maxItersPerDigit)
+}
+
+// initLoop is like newLoop, but resets and returns l instead of a new loop
+// checker, so that the memory of a previous loop can be reused.
+func (c *Context) initLoop(l *loop, name string, arg *Decimal, precision uint32, maxItersPerDigit int) *loop {
+	l.c = c
+	l.name = name
+	l.i = 0
+	l.precision = int32(precision)
+	l.maxIterations = 10 + uint64(maxItersPerDigit*int(precision))
+	l.arg.Set(arg)
+	l.prevZ.SetFinite(0, 0)
+	l.delta.SetFinite(0, 0)
+	return l
 }
 
 // done reports whether the loop is done. If it does not converge
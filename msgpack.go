@@ -0,0 +1,139 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MsgpackExtType is the MessagePack extension type used for Decimals. It is in
+// the application-defined range [0, 127]. The extension data is the binary
+// encoding of the Decimal, as produced by AppendBinary, and is written with the
+// smallest fixext or ext format that holds it.
+const MsgpackExtType int8 = 'D'
+
+const (
+	msgpackFixExt1  = 0xd4
+	msgpackFixExt2  = 0xd5
+	msgpackFixExt4  = 0xd6
+	msgpackFixExt8  = 0xd7
+	msgpackFixExt16 = 0xd8
+	msgpackExt8     = 0xc7
+	msgpackExt16    = 0xc8
+	msgpackExt32    = 0xc9
+)
+
+var errMsgpackShort = errors.New("msgpack: unexpected end of data")
+
+// AppendMsgpack appends d to buf as a MessagePack extension of type
+// MsgpackExtType and returns the extended buffer.
+func (d *Decimal) AppendMsgpack(buf []byte) ([]byte, error) {
+	// Encode the payload after a maximal header, and then move it into place
+	// if a shorter header suffices.
+	const maxHeader = 6
+	start := len(buf)
+	for i := 0; i < maxHeader; i++ {
+		buf = append(buf, 0)
+	}
+	buf, err := d.AppendBinary(buf)
+	if err != nil {
+		return nil, err
+	}
+	n := len(buf) - start - maxHeader
+	var header []byte
+	var scratch [maxHeader]byte
+	switch {
+	case n == 1:
+		header = append(scratch[:0], msgpackFixExt1)
+	case n == 2:
+		header = append(scratch[:0], msgpackFixExt2)
+	case n == 4:
+		header = append(scratch[:0], msgpackFixExt4)
+	case n == 8:
+		header = append(scratch[:0], msgpackFixExt8)
+	case n == 16:
+		header = append(scratch[:0], msgpackFixExt16)
+	case n <= math.MaxUint8:
+		header = append(scratch[:0], msgpackExt8, byte(n))
+	case n <= math.MaxUint16:
+		header = append(scratch[:0], msgpackExt16, byte(n>>8), byte(n))
+	default:
+		header = append(scratch[:0], msgpackExt32, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	header = append(header, byte(MsgpackExtType))
+	copy(buf[start+len(header):], buf[start+maxHeader:])
+	copy(buf[start:], header)
+	return buf[:start+len(header)+n], nil
+}
+
+// MarshalMsgpack returns d as a MessagePack extension of type MsgpackExtType.
+func (d *Decimal) MarshalMsgpack() ([]byte, error) {
+	return d.AppendMsgpack(nil)
+}
+
+// UnmarshalMsgpack sets d to the value of data, which must contain a single
+// MessagePack extension of type MsgpackExtType.
+func (d *Decimal) UnmarshalMsgpack(data []byte) error {
+	if len(data) == 0 {
+		return errMsgpackShort
+	}
+	var n, header int
+	switch data[0] {
+	case msgpackFixExt1:
+		n, header = 1, 1
+	case msgpackFixExt2:
+		n, header = 2, 1
+	case msgpackFixExt4:
+		n, header = 4, 1
+	case msgpackFixExt8:
+		n, header = 8, 1
+	case msgpackFixExt16:
+		n, header = 16, 1
+	case msgpackExt8:
+		if len(data) < 2 {
+			return errMsgpackShort
+		}
+		n, header = int(data[1]), 2
+	case msgpackExt16:
+		if len(data) < 3 {
+			return errMsgpackShort
+		}
+		n, header = int(binary.BigEndian.Uint16(data[1:])), 3
+	case msgpackExt32:
+		if len(data) < 5 {
+			return errMsgpackShort
+		}
+		n, header = int(binary.BigEndian.Uint32(data[1:])), 5
+	default:
+		return fmt.Errorf("msgpack: format 0x%02x is not an extension", data[0])
+	}
+	if len(data) < header+1 {
+		return errMsgpackShort
+	}
+	if typ := int8(data[header]); typ != MsgpackExtType {
+		return fmt.Errorf("msgpack: extension type %d is not a Decimal", typ)
+	}
+	data = data[header+1:]
+	if len(data) != n {
+		if len(data) < n {
+			return errMsgpackShort
+		}
+		return errors.New("msgpack: trailing data")
+	}
+	return d.UnmarshalBinary(data)
+}
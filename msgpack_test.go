@@ -0,0 +1,126 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMsgpackEncoding(t *testing.T) {
+	tests := []struct {
+		s   string
+		hex string
+	}{
+		// fixext 2.
+		{s: "NaN", hex: "d544" + "0103"},
+		{s: "-Infinity", hex: "d544" + "0105"},
+		// fixext 4.
+		{s: "1", hex: "d644" + "01080001"},
+		{s: "-1.5", hex: "d644" + "010c010f"},
+		// ext 8.
+		{s: "128", hex: "c70544" + "0108008001"},
+		{s: "18446744073709551616", hex: "c70d44" + "0100000901" + "0000000000000000"},
+		// fixext 8.
+		{s: "268435456", hex: "d744" + "0108008080808001"},
+		// fixext 16.
+		{s: "309485009821345068724781056", hex: "d844" + "0100000c01" + strings.Repeat("00", 11)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.s, func(t *testing.T) {
+			d := newDecimal(t, testCtx, tc.s)
+			b, err := d.MarshalMsgpack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h := hex.EncodeToString(b); h != tc.hex {
+				t.Fatalf("expected %s, got %s", tc.hex, h)
+			}
+			testMsgpackRoundTrip(t, d)
+		})
+	}
+
+	// ext 16 and ext 32.
+	var d Decimal
+	d.Coeff.Lsh(bigOne, 8*1000)
+	testMsgpackRoundTrip(t, &d)
+	if b, _ := d.MarshalMsgpack(); hex.EncodeToString(b[:4]) != "c803ee44" {
+		t.Fatalf("unexpected ext 16 header %x", b[:4])
+	}
+	d.Coeff.Lsh(bigOne, 8*70000)
+	testMsgpackRoundTrip(t, &d)
+	if b, _ := d.MarshalMsgpack(); b[0] != 0xc9 || b[5] != 'D' {
+		t.Fatalf("unexpected ext 32 header %x", b[:6])
+	}
+
+	for _, s := range []string{"0", "-0", "-NaN", "sNaN", "Infinity", "273.15", "-1E-100000"} {
+		testMsgpackRoundTrip(t, newDecimal(t, testCtx, s))
+	}
+}
+
+func testMsgpackRoundTrip(t *testing.T, d *Decimal) {
+	t.Helper()
+	b, err := d.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r Decimal
+	if err := r.UnmarshalMsgpack(b); err != nil {
+		t.Fatal(err)
+	}
+	if r.Form != d.Form || r.Negative != d.Negative || (d.Form == Finite && r.CmpTotal(d) != 0) {
+		t.Fatalf("expected %.20s, got %.20s", d, &r)
+	}
+	buf, err := d.AppendMsgpack([]byte("prefix"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "prefix"+string(b) {
+		t.Fatalf("unexpected append result %.40x", buf)
+	}
+}
+
+func TestMsgpackDecodeErrors(t *testing.T) {
+	tests := []string{
+		"",
+		// Not an extension.
+		"01",
+		"c0",
+		// Wrong extension type.
+		"d60101080001",
+		// Truncated headers and data.
+		"d6",
+		"d6440108",
+		"c7",
+		"c70544010800",
+		"c8",
+		"c900",
+		// Trailing data.
+		"d5440103" + "00",
+		// Invalid binary encoding.
+		"d5440203",
+	}
+	for _, tc := range tests {
+		b, err := hex.DecodeString(tc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Decimal
+		if err := d.UnmarshalMsgpack(b); err == nil {
+			t.Errorf("%s: expected error, got %s", tc, &d)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The MySQL packed DECIMAL(M,D) format, as produced by decimal2bin, stores
+// the M-D integer digits and the D fractional digits of a value separately, in
+// big-endian groups of 9 decimal digits per 4 bytes. The leftover integer
+// digits form a shorter group at the start, and the leftover fractional
+// digits a shorter group at the end, using mysqlDigitBytes bytes. The first
+// bit of the encoding is set for non-negative values, and all bytes of
+// negative values are complemented, so the encoding sorts like the values.
+const (
+	// MaxMySQLPrecision is the largest precision of a MySQL DECIMAL.
+	MaxMySQLPrecision = 65
+	// MaxMySQLScale is the largest scale of a MySQL DECIMAL.
+	MaxMySQLScale = 30
+
+	mysqlGroupDigits = 9
+	mysqlGroupBytes  = 4
+)
+
+// mysqlDigitBytes is the number of bytes used by a group of n digits.
+var mysqlDigitBytes = [mysqlGroupDigits + 1]int{0, 1, 1, 2, 2, 3, 3, 4, 4, 4}
+
+// MySQLBinarySize returns the size in bytes of a DECIMAL(precision, scale) in
+// the MySQL packed format.
+func MySQLBinarySize(precision, scale int) (int, error) {
+	if err := checkMySQLType(precision, scale); err != nil {
+		return 0, err
+	}
+	return mysqlPartSize(precision-scale) + mysqlPartSize(scale), nil
+}
+
+func mysqlPartSize(digits int) int {
+	return digits/mysqlGroupDigits*mysqlGroupBytes + mysqlDigitBytes[digits%mysqlGroupDigits]
+}
+
+func checkMySQLType(precision, scale int) error {
+	if precision < 1 || precision > MaxMySQLPrecision || scale < 0 || scale > MaxMySQLScale || scale > precision {
+		return fmt.Errorf("mysql decimal: invalid type DECIMAL(%d,%d)", precision, scale)
+	}
+	return nil
+}
+
+// AppendMySQLBinary appends d to buf in the MySQL packed format of a
+// DECIMAL(precision, scale) and returns the extended buffer. An error is
+// returned if d is not finite or cannot be represented exactly by the type.
+// Negative zero is encoded as zero.
+func (d *Decimal) AppendMySQLBinary(buf []byte, precision, scale int) ([]byte, error) {
+	if err := checkMySQLType(precision, scale); err != nil {
+		return nil, err
+	}
+	if d.Form != Finite {
+		return nil, fmt.Errorf("mysql decimal: %s is not finite", d.String())
+	}
+	intg := precision - scale
+	var x Decimal
+	if !d.IsZero() {
+		// Check the number of integer digits before quantizing, which would
+		// otherwise allocate a large coefficient for large exponents.
+		if int64(d.NumDigits())+int64(d.Exponent) > int64(intg) {
+			return nil, fmt.Errorf("mysql decimal: %s out of range for DECIMAL(%d,%d)", d.String(), precision, scale)
+		}
+		if res := BaseContext.quantize(&x, d, -int32(scale)); res.Inexact() {
+			return nil, fmt.Errorf("mysql decimal: %s cannot be represented with scale %d", d.String(), scale)
+		}
+	}
+
+	// Left-pad the digits of the coefficient to the precision.
+	var scratch [MaxMySQLPrecision]byte
+	digits := x.Coeff.Append(scratch[:0], 10)
+	if len(digits) > precision {
+		return nil, fmt.Errorf("mysql decimal: %s out of range for DECIMAL(%d,%d)", d.String(), precision, scale)
+	}
+	pad := precision - len(digits)
+	copy(scratch[pad:precision], digits)
+	for i := 0; i < pad; i++ {
+		scratch[i] = '0'
+	}
+	digits = scratch[:precision]
+
+	// The groups written below add up to MySQLBinarySize(precision, scale).
+	start := len(buf)
+	// The integer part, with the partial group first.
+	if n := intg % mysqlGroupDigits; n > 0 {
+		buf = appendMySQLGroup(buf, digits[:n])
+		digits = digits[n:]
+	}
+	for ; len(digits) > scale; digits = digits[mysqlGroupDigits:] {
+		buf = appendMySQLGroup(buf, digits[:mysqlGroupDigits])
+	}
+	// The fractional part, with the partial group last.
+	for ; len(digits) >= mysqlGroupDigits; digits = digits[mysqlGroupDigits:] {
+		buf = appendMySQLGroup(buf, digits[:mysqlGroupDigits])
+	}
+	if len(digits) > 0 {
+		buf = appendMySQLGroup(buf, digits)
+	}
+
+	if d.Negative && !x.IsZero() {
+		for i := start; i < len(buf); i++ {
+			buf[i] = ^buf[i]
+		}
+	}
+	buf[start] ^= 0x80
+	return buf, nil
+}
+
+// appendMySQLGroup appends the group of digits in big-endian order, using
+// mysqlDigitBytes[len(digits)] bytes.
+func appendMySQLGroup(buf []byte, digits []byte) []byte {
+	var v uint32
+	for _, c := range digits {
+		v = v*10 + uint32(c-'0')
+	}
+	for i := mysqlDigitBytes[len(digits)] - 1; i >= 0; i-- {
+		buf = append(buf, byte(v>>(8*i)))
+	}
+	return buf
+}
+
+// SetMySQLBinary sets d to the value of b, which is a DECIMAL(precision,
+// scale) in the MySQL packed format, and returns d. The length of b must be
+// MySQLBinarySize(precision, scale). The exponent of d is -scale.
+func (d *Decimal) SetMySQLBinary(b []byte, precision, scale int) (*Decimal, error) {
+	size, err := MySQLBinarySize(precision, scale)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("mysql decimal: expected %d bytes for DECIMAL(%d,%d), got %d", size, precision, scale, len(b))
+	}
+	var mask byte
+	neg := b[0]&0x80 == 0
+	if neg {
+		mask = 0xff
+	}
+	// byteAt returns the i'th byte of b with the sign bit and complement of
+	// negative values removed.
+	byteAt := func(i int) byte {
+		c := b[i] ^ mask
+		if i == 0 {
+			c ^= 0x80
+		}
+		return c
+	}
+
+	var digits [MaxMySQLPrecision]byte
+	n := 0
+	pos := 0
+	// readGroup decodes a group of k digits.
+	readGroup := func(k int) error {
+		var v uint32
+		for i := 0; i < mysqlDigitBytes[k]; i++ {
+			v = v<<8 | uint32(byteAt(pos))
+			pos++
+		}
+		for i := k - 1; i >= 0; i-- {
+			digits[n+i] = byte('0' + v%10)
+			v /= 10
+		}
+		if v != 0 {
+			return errors.New("mysql decimal: invalid digit group")
+		}
+		n += k
+		return nil
+	}
+	intg := precision - scale
+	// The integer part has a leading partial group, and the fractional part a
+	// trailing one.
+	full := intg/mysqlGroupDigits + scale/mysqlGroupDigits
+	if err := readGroup(intg % mysqlGroupDigits); err != nil {
+		return nil, err
+	}
+	for i := 0; i < full; i++ {
+		if err := readGroup(mysqlGroupDigits); err != nil {
+			return nil, err
+		}
+	}
+	if err := readGroup(scale % mysqlGroupDigits); err != nil {
+		return nil, err
+	}
+
+	var coeff BigInt
+	if _, ok := coeff.SetString(string(digits[:n]), 10); !ok {
+		return nil, errors.New("mysql decimal: invalid digits")
+	}
+	d.Form = Finite
+	d.Negative = neg && coeff.Sign() != 0
+	d.Exponent = -int32(scale)
+	d.Coeff.Set(&coeff)
+	return d, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMySQLBinary(t *testing.T) {
+	tests := []struct {
+		s                string
+		precision, scale int
+		hex              string
+		out              string
+	}{
+		// The example from the MySQL reference manual.
+		{s: "1234567890.1234", precision: 14, scale: 4, hex: "810dfb38d204d2"},
+		{s: "-1234567890.1234", precision: 14, scale: 4, hex: "7ef204c72dfb2d"},
+		{s: "0", precision: 10, scale: 2, hex: "8000000000", out: "0.00"},
+		{s: "-0.00", precision: 10, scale: 2, hex: "8000000000", out: "0.00"},
+		{s: "-1.50", precision: 10, scale: 2, hex: "7ffffffecd"},
+		{s: "1.5", precision: 10, scale: 2, hex: "8000000132", out: "1.50"},
+		{s: "12345", precision: 5, scale: 0, hex: "803039"},
+		{s: "1.2345E+4", precision: 5, scale: 0, hex: "803039", out: "12345"},
+		{s: "9", precision: 1, scale: 0, hex: "89"},
+		{s: "0.1", precision: 1, scale: 1, hex: "81"},
+		{s: "1E-10", precision: 20, scale: 10, hex: "80000000000000000001", out: "1E-10"},
+		{s: "123456789.123456789", precision: 18, scale: 9, hex: "875bcd15075bcd15"},
+		{
+			s:         strings.Repeat("9", 35) + "." + strings.Repeat("9", 30),
+			precision: 65, scale: 30,
+			hex: "85f5e0ff" + strings.Repeat("3b9ac9ff", 6) + "03e7",
+		},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		b, err := d.AppendMySQLBinary([]byte("x"), tc.precision, tc.scale)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if h := hex.EncodeToString(b[1:]); h != tc.hex {
+			t.Errorf("%s: expected %s, got %s", tc.s, tc.hex, h)
+			continue
+		}
+		if size, _ := MySQLBinarySize(tc.precision, tc.scale); size != len(b)-1 {
+			t.Fatalf("%s: expected size %d, got %d", tc.s, size, len(b)-1)
+		}
+		var r Decimal
+		if _, err := r.SetMySQLBinary(b[1:], tc.precision, tc.scale); err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		out := tc.out
+		if out == "" {
+			out = tc.s
+		}
+		if r.String() != out {
+			t.Fatalf("%s: expected %s, got %s", tc.s, out, &r)
+		}
+	}
+}
+
+func TestMySQLBinaryErrors(t *testing.T) {
+	encode := []struct {
+		s                string
+		precision, scale int
+	}{
+		{s: "1234.5", precision: 4, scale: 1},
+		{s: "1E+100000", precision: 65, scale: 0},
+		{s: "1.25", precision: 5, scale: 1},
+		{s: "1E-31", precision: 65, scale: 30},
+		{s: "NaN", precision: 5, scale: 1},
+		{s: "-Inf", precision: 5, scale: 1},
+		{s: "1", precision: 66, scale: 0},
+		{s: "1", precision: 0, scale: 0},
+		{s: "1", precision: 5, scale: 6},
+		{s: "1", precision: 40, scale: 31},
+	}
+	for _, tc := range encode {
+		d := newDecimal(t, testCtx, tc.s)
+		if b, err := d.AppendMySQLBinary(nil, tc.precision, tc.scale); err == nil {
+			t.Errorf("%s DECIMAL(%d,%d): expected error, got %x", tc.s, tc.precision, tc.scale, b)
+		}
+	}
+
+	decode := []struct {
+		hex              string
+		precision, scale int
+	}{
+		{hex: "", precision: 1, scale: 0},
+		{hex: "8080", precision: 1, scale: 0},
+		{hex: "8a", precision: 1, scale: 0},
+		{hex: "75", precision: 1, scale: 0},
+		{hex: "bb9aca00", precision: 9, scale: 0},
+		{hex: "80", precision: 66, scale: 0},
+	}
+	for _, tc := range decode {
+		b, err := hex.DecodeString(tc.hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Decimal
+		if _, err := d.SetMySQLBinary(b, tc.precision, tc.scale); err == nil {
+			t.Errorf("%s DECIMAL(%d,%d): expected error, got %s", tc.hex, tc.precision, tc.scale, &d)
+		}
+	}
+}
+
+func TestMySQLBinarySize(t *testing.T) {
+	var d Decimal
+	for precision := 1; precision <= MaxMySQLPrecision; precision++ {
+		for scale := 0; scale <= MaxMySQLScale && scale <= precision; scale++ {
+			b, err := d.AppendMySQLBinary(nil, precision, scale)
+			if err != nil {
+				t.Fatalf("DECIMAL(%d,%d): %v", precision, scale, err)
+			}
+			if size, _ := MySQLBinarySize(precision, scale); size != len(b) {
+				t.Fatalf("DECIMAL(%d,%d): expected size %d, got %d", precision, scale, size, len(b))
+			}
+		}
+	}
+}
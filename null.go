@@ -0,0 +1,130 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import "bytes"
+
+// NewNullDecimal returns a NullDecimal holding a copy of d, or NULL if d is
+// nil.
+func NewNullDecimal(d *Decimal) NullDecimal {
+	var nd NullDecimal
+	if d != nil {
+		nd.Decimal.Set(d)
+		nd.Valid = true
+	}
+	return nd
+}
+
+// NewNullDecimalFromString returns a valid NullDecimal holding the value of s,
+// as parsed by NewFromString.
+func NewNullDecimalFromString(s string) (NullDecimal, Condition, error) {
+	var nd NullDecimal
+	_, res, err := nd.Decimal.SetString(s)
+	if err != nil {
+		return NullDecimal{}, res, err
+	}
+	nd.Valid = true
+	return nd, res, nil
+}
+
+// Cmp compares nd and x and returns:
+//
+//	-1 if nd <  x
+//	 0 if nd == x
+//	+1 if nd >  x
+//
+// NULL is equal to NULL and less than any valid value. Valid values are
+// compared with (*Decimal).Cmp, so the result is undefined if either is NaN.
+func (nd *NullDecimal) Cmp(x *NullDecimal) int {
+	switch {
+	case !nd.Valid && !x.Valid:
+		return 0
+	case !nd.Valid:
+		return -1
+	case !x.Valid:
+		return 1
+	}
+	return nd.Decimal.Cmp(&x.Decimal)
+}
+
+var jsonNull = []byte("null")
+
+// MarshalJSON implements the json.Marshaler interface. NULL is encoded as the
+// JSON null, and valid values are encoded like (*Decimal).MarshalJSON.
+func (nd NullDecimal) MarshalJSON() ([]byte, error) {
+	if !nd.Valid {
+		return append([]byte(nil), jsonNull...), nil
+	}
+	return nd.Decimal.MarshalJSON()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The JSON null sets
+// nd to NULL, and any other input is decoded like (*Decimal).UnmarshalJSON.
+func (nd *NullDecimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		nd.Valid = false
+		return nil
+	}
+	if err := nd.Decimal.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	nd.Valid = true
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface. NULL is
+// encoded as the empty string, which is not a valid Decimal.
+func (nd NullDecimal) MarshalText() ([]byte, error) {
+	if !nd.Valid {
+		return []byte{}, nil
+	}
+	return nd.Decimal.MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. The empty
+// string sets nd to NULL.
+func (nd *NullDecimal) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		nd.Valid = false
+		return nil
+	}
+	if err := nd.Decimal.UnmarshalText(b); err != nil {
+		return err
+	}
+	nd.Valid = true
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. NULL is
+// encoded as an empty slice, and valid values like (*Decimal).MarshalBinary.
+func (nd NullDecimal) MarshalBinary() ([]byte, error) {
+	if !nd.Valid {
+		return []byte{}, nil
+	}
+	return nd.Decimal.MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (nd *NullDecimal) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		nd.Valid = false
+		return nil
+	}
+	if err := nd.Decimal.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	nd.Valid = true
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestNullDecimalConstructors(t *testing.T) {
+	if nd := NewNullDecimal(nil); nd.Valid {
+		t.Fatal("expected NULL")
+	}
+	d := New(15, -1)
+	nd := NewNullDecimal(d)
+	d.SetInt64(2)
+	if !nd.Valid || nd.Decimal.String() != "1.5" {
+		t.Fatalf("expected valid 1.5, got %v %s", nd.Valid, &nd.Decimal)
+	}
+	nd, _, err := NewNullDecimalFromString("-1.25E+3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !nd.Valid || nd.Decimal.String() != "-1.25E+3" {
+		t.Fatalf("expected valid -1.25E+3, got %v %s", nd.Valid, &nd.Decimal)
+	}
+	if nd, _, err := NewNullDecimalFromString("x"); err == nil || nd.Valid {
+		t.Fatalf("expected error, got %v %s", nd.Valid, &nd.Decimal)
+	}
+}
+
+func TestNullDecimalCmp(t *testing.T) {
+	null := NullDecimal{}
+	one := NewNullDecimal(New(1, 0))
+	two := NewNullDecimal(New(2, 0))
+	tests := []struct {
+		x, y     NullDecimal
+		expected int
+	}{
+		{null, null, 0},
+		{null, one, -1},
+		{one, null, 1},
+		{one, one, 0},
+		{one, two, -1},
+		{two, one, 1},
+		{NewNullDecimal(New(-5, 0)), null, 1},
+		// The Decimal of a NULL is ignored.
+		{NullDecimal{Decimal: *New(3, 0)}, null, 0},
+	}
+	for i, tc := range tests {
+		if c := tc.x.Cmp(&tc.y); c != tc.expected {
+			t.Errorf("%d: expected %d, got %d", i, tc.expected, c)
+		}
+	}
+}
+
+func TestNullDecimalJSON(t *testing.T) {
+	type row struct {
+		A NullDecimal
+		B *NullDecimal
+		C NullDecimal `json:",omitempty"`
+	}
+	b := NewNullDecimal(New(-125, -2))
+	in := row{A: NewNullDecimal(New(15, -1)), B: &b}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"A":"1.5","B":"-1.25","C":null}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+
+	var out row
+	out.C = NewNullDecimal(New(7, 0))
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.A.Cmp(&in.A) != 0 || out.B.Cmp(in.B) != 0 || out.C.Valid {
+		t.Fatalf("unexpected round trip %+v", out)
+	}
+
+	for _, tc := range []struct {
+		s        string
+		expected string
+	}{
+		{s: `null`, expected: ""},
+		{s: ` null `, expected: ""},
+		{s: `1.5`, expected: "1.5"},
+		{s: `"NaN"`, expected: "NaN"},
+	} {
+		var nd NullDecimal
+		if err := nd.UnmarshalJSON([]byte(tc.s)); err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if tc.expected == "" {
+			if nd.Valid {
+				t.Fatalf("%s: expected NULL", tc.s)
+			}
+		} else if !nd.Valid || nd.Decimal.String() != tc.expected {
+			t.Fatalf("%s: expected %s, got %v %s", tc.s, tc.expected, nd.Valid, &nd.Decimal)
+		}
+	}
+	var nd NullDecimal
+	if err := nd.UnmarshalJSON([]byte(`true`)); err == nil || nd.Valid {
+		t.Fatal("expected error")
+	}
+}
+
+func TestNullDecimalText(t *testing.T) {
+	for _, nd := range []NullDecimal{{}, NewNullDecimal(New(-15, -1)), NewNullDecimal(New(0, 2))} {
+		b, err := nd.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !nd.Valid && len(b) != 0 {
+			t.Fatalf("expected empty text for NULL, got %q", b)
+		}
+		r := NewNullDecimal(New(9, 0))
+		if err := r.UnmarshalText(b); err != nil {
+			t.Fatal(err)
+		}
+		if r.Valid != nd.Valid || r.Cmp(&nd) != 0 {
+			t.Fatalf("expected %q, got %v %s", b, r.Valid, &r.Decimal)
+		}
+	}
+	var nd NullDecimal
+	if err := nd.UnmarshalText([]byte("x")); err == nil || nd.Valid {
+		t.Fatal("expected error")
+	}
+}
+
+func TestNullDecimalBinary(t *testing.T) {
+	for _, nd := range []NullDecimal{{}, NewNullDecimal(New(-15, -1)), NewNullDecimal(&Decimal{Form: NaN})} {
+		b, err := nd.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := NewNullDecimal(New(9, 0))
+		if err := r.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+		if r.Valid != nd.Valid || (nd.Valid && r.Decimal.CmpTotal(&nd.Decimal) != 0) {
+			t.Fatalf("expected %v %s, got %v %s", nd.Valid, &nd.Decimal, r.Valid, &r.Decimal)
+		}
+	}
+
+	// NullDecimal is encoded by gob through MarshalBinary.
+	type row struct {
+		A, B NullDecimal
+	}
+	in := row{A: NewNullDecimal(New(12345, -2))}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out row
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.A.Cmp(&in.A) != 0 || !out.A.Valid || out.B.Valid {
+		t.Fatalf("unexpected round trip %+v", out)
+	}
+}
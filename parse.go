@@ -0,0 +1,256 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// ParseOptions is a set of extensions to the grammar of numeric strings. The
+// base grammar is an optional '-' sign followed by digits, with an optional
+// decimal point between two digits, as in "-1.5". Each option allows more
+// syntax.
+type ParseOptions uint32
+
+const (
+	// ParsePlus allows a leading '+' sign: "+1".
+	ParsePlus ParseOptions = 1 << iota
+	// ParseSpecials allows the special values "Infinity", "Inf", "NaN" and
+	// "sNaN", in any case and with an optional sign, and NaN payload digits:
+	// "-inf", "NaN123".
+	ParseSpecials
+	// ParseLeadingDot allows a decimal point with no digits before it: ".5".
+	ParseLeadingDot
+	// ParseTrailingDot allows a decimal point with no digits after it: "5.".
+	ParseTrailingDot
+	// ParseLeadingZeros allows an integer part with leading zeros: "007".
+	ParseLeadingZeros
+	// ParseExponent allows an exponent: "1.5E+3", "1e-3".
+	ParseExponent
+	// ParseSpace allows leading and trailing white space: " 1.5\n".
+	ParseSpace
+	// ParseUnderscores allows single underscores between the digits of the
+	// coefficient: "1_000.5".
+	ParseUnderscores
+	// ParsePercent allows a trailing percent sign, which divides the value by
+	// 100: "12.5%" is 0.125.
+	ParsePercent
+	// ParseUnicodeMinus allows the minus sign U+2212 in place of '-': "−1".
+	ParseUnicodeMinus
+)
+
+const (
+	// ParseGDA is the numeric string syntax of the General Decimal Arithmetic
+	// specification. It is the grammar of SetString.
+	ParseGDA = ParsePlus | ParseSpecials | ParseLeadingDot | ParseTrailingDot | ParseLeadingZeros | ParseExponent
+	// ParseSQL is the syntax of signed SQL numeric literals, such as "-1.5",
+	// ".5" and "1E+3". It is ParseGDA without the special values.
+	ParseSQL = ParsePlus | ParseLeadingDot | ParseTrailingDot | ParseLeadingZeros | ParseExponent
+	// ParseJSON is the syntax of JSON numbers (RFC 8259), which have no '+'
+	// sign, leading zeros, bare decimal points or special values.
+	ParseJSON = ParseExponent
+	// ParseLenient is for human input. It extends ParseGDA with surrounding
+	// white space, digit separators, percentages and the Unicode minus sign.
+	ParseLenient = ParseGDA | ParseSpace | ParseUnderscores | ParsePercent | ParseUnicodeMinus
+)
+
+const unicodeMinus = "−"
+
+// SetStringOptions is like SetString, but parses s with the grammar of opts
+// instead of ParseGDA.
+func (c *Context) SetStringOptions(d *Decimal, s string, opts ParseOptions) (*Decimal, Condition, error) {
+	res, err := d.setString(c, s, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	res |= c.round(d, d)
+	_, err = c.goError(res)
+	return d, res, err
+}
+
+// SetStringBytesOptions is like SetStringOptions, but parses b without
+// converting it to a string.
+func (c *Context) SetStringBytesOptions(d *Decimal, b []byte, opts ParseOptions) (*Decimal, Condition, error) {
+	// setString does not retain its argument, so b can be viewed as a string
+	// without copying it.
+	return c.SetStringOptions(d, *(*string)(unsafe.Pointer(&b)), opts)
+}
+
+// maxUint64Digits is the number of decimal digits that always fit in a
+// uint64.
+const maxUint64Digits = 19
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// hasPrefixFold reports whether s begins with the lower-case ASCII prefix,
+// ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// setString parses in into d with the grammar of opts. It does not retain in
+// or allocate for coefficients of up to 19 digits, which allows
+// SetStringBytes to pass it a string that aliases a byte slice.
+func (d *Decimal) setString(c *Context, in string, opts ParseOptions) (Condition, error) {
+	d.Negative = false
+	d.Exponent = 0
+	d.Coeff.SetInt64(0)
+	// Until there are no parse errors, leave as NaN.
+	d.Form = NaN
+	// s is in without trailing white space, so offsets in s are offsets in in.
+	s, i := in, 0
+	if opts&ParseSpace != 0 {
+		s = strings.TrimRightFunc(in, unicode.IsSpace)
+		i = len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+	}
+	switch {
+	case i < len(s) && s[i] == '-':
+		d.Negative = true
+		i++
+	case i < len(s) && s[i] == '+' && opts&ParsePlus != 0:
+		i++
+	case opts&ParseUnicodeMinus != 0 && strings.HasPrefix(s[i:], unicodeMinus):
+		d.Negative = true
+		i += len(unicodeMinus)
+	}
+	if opts&ParseSpecials != 0 {
+		switch rest := s[i:]; {
+		case strings.EqualFold(rest, "infinity"), strings.EqualFold(rest, "inf"):
+			d.Form = Infinite
+			return 0, nil
+		case hasPrefixFold(rest, "nan"), hasPrefixFold(rest, "snan"):
+			form := NaN
+			if rest[0] == 's' || rest[0] == 'S' {
+				form = NaNSignaling
+				i++
+			}
+			i += len("nan")
+			// We ignore the payload digits, but must verify them.
+			var payload uint64
+			for ; i < len(s); i++ {
+				ch := s[i] - '0'
+				if ch > 9 {
+					return 0, newParseError(in, i, "invalid NaN payload")
+				}
+				if payload > (math.MaxUint64-uint64(ch))/10 {
+					return 0, newParseError(in, i, "NaN payload value out of range")
+				}
+				payload = payload*10 + uint64(ch)
+			}
+			d.Form = form
+			return 0, nil
+		}
+	}
+
+	// The coefficient, with an optional decimal point.
+	start, dot := i, -1
+	var digits, fracDigits int
+	var u uint64
+	underscores := false
+	for ; i < len(s); i++ {
+		ch := s[i]
+		if isDigit(ch) {
+			if digits < maxUint64Digits {
+				u = u*10 + uint64(ch-'0')
+			}
+			digits++
+			if dot >= 0 {
+				fracDigits++
+			}
+		} else if ch == '.' && dot < 0 {
+			dot = i
+		} else if ch == '_' && opts&ParseUnderscores != 0 {
+			if i == start || !isDigit(s[i-1]) || i+1 == len(s) || !isDigit(s[i+1]) {
+				return 0, newParseError(in, i, "misplaced underscore")
+			}
+			underscores = true
+		} else {
+			break
+		}
+	}
+	if digits == 0 {
+		return 0, newParseError(in, i, "missing digits")
+	}
+	intDigits := digits - fracDigits
+	if dot >= 0 && intDigits == 0 && opts&ParseLeadingDot == 0 {
+		return 0, newParseError(in, dot, "missing digits before decimal point")
+	}
+	if dot >= 0 && fracDigits == 0 && opts&ParseTrailingDot == 0 {
+		return 0, newParseError(in, dot+1, "missing digits after decimal point")
+	}
+	if intDigits > 1 && s[start] == '0' && opts&ParseLeadingZeros == 0 {
+		return 0, newParseError(in, start, "leading zero")
+	}
+	end := i
+
+	var exp int64
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') && opts&ParseExponent != 0 {
+		i++
+		neg := false
+		if i < len(s) && (s[i] == '-' || s[i] == '+') {
+			neg = s[i] == '-'
+			i++
+		}
+		expStart := i
+		for ; i < len(s) && isDigit(s[i]); i++ {
+			exp = exp*10 + int64(s[i]-'0')
+			if exp > -math.MinInt32 {
+				return 0, newParseError(in, expStart, errExponentOutOfRangeStr)
+			}
+		}
+		if i == expStart {
+			return 0, newParseError(in, i, "missing exponent digits")
+		}
+		if neg {
+			exp = -exp
+		} else if exp > math.MaxInt32 {
+			return 0, newParseError(in, expStart, errExponentOutOfRangeStr)
+		}
+	}
+	var percent int64
+	if i < len(s) && s[i] == '%' && opts&ParsePercent != 0 {
+		percent = 2
+		i++
+	}
+	if i < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[i:])
+		return 0, newParseError(in, i, fmt.Sprintf("unexpected character %q", r))
+	}
+
+	switch {
+	case digits <= maxUint64Digits:
+		d.Coeff.SetUint64(u)
+	case dot < 0 && !underscores:
+		d.Coeff.setDigits(s[start:end])
+	default:
+		buf := make([]byte, 0, digits)
+		for j := start; j < end; j++ {
+			if isDigit(s[j]) {
+				buf = append(buf, s[j])
+			}
+		}
+		d.Coeff.setDigits(string(buf))
+	}
+	// No parse errors, can now flag as finite.
+	d.Form = Finite
+	return c.goError(d.setExponent(c, unknownNumDigits, 0, exp, -int64(fracDigits), -percent))
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var parsePresets = []struct {
+	name string
+	opts ParseOptions
+}{
+	{"GDA", ParseGDA},
+	{"SQL", ParseSQL},
+	{"JSON", ParseJSON},
+	{"Lenient", ParseLenient},
+}
+
+func TestParseOptions(t *testing.T) {
+	const reject = "error"
+	tests := []struct {
+		s string
+		// Expected results for GDA, SQL, JSON and Lenient.
+		expected [4]string
+	}{
+		{"1.5", [4]string{"1.5", "1.5", "1.5", "1.5"}},
+		{"-0", [4]string{"-0", "-0", "-0", "-0"}},
+		{"0.5", [4]string{"0.5", "0.5", "0.5", "0.5"}},
+		{"-1.25E-7", [4]string{"-1.25E-7", "-1.25E-7", "-1.25E-7", "-1.25E-7"}},
+		{"1e+3", [4]string{"1E+3", "1E+3", "1E+3", "1E+3"}},
+		{"+1", [4]string{"1", "1", reject, "1"}},
+		{"01", [4]string{"1", "1", reject, "1"}},
+		{"-00.5", [4]string{"-0.5", "-0.5", reject, "-0.5"}},
+		{".5", [4]string{"0.5", "0.5", reject, "0.5"}},
+		{"5.", [4]string{"5", "5", reject, "5"}},
+		{"5.e3", [4]string{"5E+3", "5E+3", reject, "5E+3"}},
+		{"Inf", [4]string{"Infinity", reject, reject, "Infinity"}},
+		{"-infinity", [4]string{"-Infinity", reject, reject, "-Infinity"}},
+		{"NaN", [4]string{"NaN", reject, reject, "NaN"}},
+		{"sNaN12", [4]string{"sNaN", reject, reject, "sNaN"}},
+		{" 1.5\n", [4]string{reject, reject, reject, "1.5"}},
+		{" -2 ", [4]string{reject, reject, reject, "-2"}},
+		{" inf ", [4]string{reject, reject, reject, "Infinity"}},
+		{"1_000.000_5", [4]string{reject, reject, reject, "1000.0005"}},
+		{"12.5%", [4]string{reject, reject, reject, "0.125"}},
+		{"1e3%", [4]string{reject, reject, reject, "1E+1"}},
+		{"−1.5", [4]string{reject, reject, reject, "-1.5"}},
+		{"−Inf", [4]string{reject, reject, reject, "-Infinity"}},
+		{"1_000_000_000_000_000_000_000", [4]string{reject, reject, reject, "1000000000000000000000"}},
+		{"1__0", [4]string{reject, reject, reject, reject}},
+		{"_1", [4]string{reject, reject, reject, reject}},
+		{"1_", [4]string{reject, reject, reject, reject}},
+		{"1_.5", [4]string{reject, reject, reject, reject}},
+		{"1._5", [4]string{reject, reject, reject, reject}},
+		{"1e_5", [4]string{reject, reject, reject, reject}},
+		{"%", [4]string{reject, reject, reject, reject}},
+		{"5%%", [4]string{reject, reject, reject, reject}},
+		{"1 0", [4]string{reject, reject, reject, reject}},
+		{"--1", [4]string{reject, reject, reject, reject}},
+		{"-−1", [4]string{reject, reject, reject, reject}},
+		{"", [4]string{reject, reject, reject, reject}},
+		{" ", [4]string{reject, reject, reject, reject}},
+		{".", [4]string{reject, reject, reject, reject}},
+	}
+	for _, tc := range tests {
+		for i, p := range parsePresets {
+			var d Decimal
+			_, _, err := BaseContext.SetStringOptions(&d, tc.s, p.opts)
+			if tc.expected[i] == reject {
+				var pe *ParseError
+				if !errors.As(err, &pe) {
+					t.Errorf("%s: %q: expected ParseError, got %s, %v", p.name, tc.s, &d, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s: %q: %v", p.name, tc.s, err)
+			} else if d.String() != tc.expected[i] {
+				t.Errorf("%s: %q: expected %s, got %s", p.name, tc.s, tc.expected[i], &d)
+			}
+			// The bytes variant parses identically.
+			var b Decimal
+			if _, _, err := BaseContext.SetStringBytesOptions(&b, []byte(tc.s), p.opts); err != nil || b.String() != d.String() {
+				t.Errorf("%s: %q: bytes: expected %s, got %s, %v", p.name, tc.s, &d, &b, err)
+			}
+		}
+	}
+}
+
+func TestParseOptionsErrorOffsets(t *testing.T) {
+	tests := []struct {
+		s      string
+		opts   ParseOptions
+		offset int
+		reason string
+	}{
+		{s: "+1", opts: ParseJSON, offset: 0, reason: "missing digits"},
+		{s: "01", opts: ParseJSON, offset: 0, reason: "leading zero"},
+		{s: "-.5", opts: ParseJSON, offset: 1, reason: "missing digits before decimal point"},
+		{s: "5.", opts: ParseJSON, offset: 2, reason: "missing digits after decimal point"},
+		{s: "1e5", opts: 0, offset: 1, reason: `unexpected character 'e'`},
+		{s: "  1x ", opts: ParseLenient, offset: 3, reason: `unexpected character 'x'`},
+		{s: " 1__0", opts: ParseLenient, offset: 2, reason: "misplaced underscore"},
+		{s: "1%", opts: ParseGDA, offset: 1, reason: `unexpected character '%'`},
+	}
+	for _, tc := range tests {
+		_, _, err := BaseContext.SetStringOptions(new(Decimal), tc.s, tc.opts)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%q: expected ParseError, got %v", tc.s, err)
+		}
+		if pe.Input != tc.s || pe.Offset != tc.offset || pe.Reason != tc.reason {
+			t.Errorf("%q: expected %d: %s, got %d: %s", tc.s, tc.offset, tc.reason, pe.Offset, pe.Reason)
+		}
+	}
+}
+
+// TestParseOptionsGDA checks the presets against the conversions in
+// base.decTest.
+func TestParseOptionsGDA(t *testing.T) {
+	_, tcs := readGDA(t, "base")
+	n := 0
+	for _, tc := range tcs {
+		if tc.Operation != "tosci" && tc.Operation != "toeng" {
+			continue
+		}
+		n++
+		s := tc.Operands[0]
+		syntaxError := tc.Result == "NAN" && strings.Join(tc.Conditions, "") == "conversion_syntax"
+		var gda Decimal
+		_, _, err := BaseContext.SetStringOptions(&gda, s, ParseGDA)
+		// GDA signals these conditions for exponents beyond the limits of
+		// BaseContext, which traps them. Without a precision, BaseContext
+		// rounds every subnormal, which also signals Underflow.
+		var rangeCond Condition
+		for _, cond := range tc.Conditions {
+			switch cond {
+			case "overflow":
+				rangeCond |= Overflow
+			case "underflow", "subnormal":
+				rangeCond |= Underflow | Subnormal
+			}
+		}
+		var pe *ParseError
+		if err != nil && !errors.As(err, &pe) {
+			if rangeCond == 0 || err.Error() != rangeCond.String() {
+				t.Errorf("%s: GDA: %q: expected %s, got %v", tc.ID, s, rangeCond, err)
+			}
+			continue
+		}
+		if pe != nil && pe.Reason == errExponentOutOfRangeStr {
+			// Exponents that don't fit in an int32 are rejected by the parser.
+			if rangeCond == 0 && !syntaxError {
+				t.Errorf("%s: GDA: %q: expected %s, got %v", tc.ID, s, rangeCond, err)
+			}
+			continue
+		}
+		if syntaxError && err == nil && (gda.Form == NaN || gda.Form == NaNSignaling) {
+			// NaN payloads longer than the precision are invalid, but parsing
+			// ignores payloads.
+			continue
+		}
+		if (err != nil) != syntaxError {
+			t.Errorf("%s: GDA: %q: expected syntax error %v, got %v", tc.ID, s, syntaxError, err)
+			continue
+		}
+		for _, p := range parsePresets[1:] {
+			var d Decimal
+			_, _, perr := BaseContext.SetStringOptions(&d, s, p.opts)
+			var accept bool
+			switch p.opts {
+			case ParseSQL:
+				accept = err == nil && gda.Form == Finite
+			case ParseJSON:
+				accept = jsonNumberRE.MatchString(s)
+			case ParseLenient:
+				// Lenient accepts a superset of GDA.
+				if err != nil {
+					continue
+				}
+				accept = true
+			}
+			if (perr == nil) != accept {
+				t.Errorf("%s: %s: %q: expected accept %v, got %v", tc.ID, p.name, s, accept, perr)
+			} else if accept && d.String() != gda.String() {
+				t.Errorf("%s: %s: %q: expected %s, got %s", tc.ID, p.name, s, &gda, &d)
+			}
+		}
+	}
+	if n < 500 {
+		t.Fatalf("expected more conversion cases, got %d", n)
+	}
+}
+
+var (
+	jsonNumberRE = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+	sqlNumberRE  = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+)
+
+// TestParseOptionsGrammar compares the presets against encoding/json and the
+// SQL numeric literal grammar on all short strings of number characters.
+func TestParseOptionsGrammar(t *testing.T) {
+	const alphabet = "019.eE+-"
+	var check func(s string)
+	check = func(s string) {
+		var d Decimal
+		_, _, jerr := BaseContext.SetStringOptions(&d, s, ParseJSON)
+		if valid := json.Valid([]byte(s)); (jerr == nil) != valid {
+			t.Errorf("JSON: %q: json.Valid %v, got %v", s, valid, jerr)
+		}
+		if (jerr == nil) != jsonNumberRE.MatchString(s) {
+			t.Errorf("JSON: %q: grammar mismatch", s)
+		}
+		for _, opts := range []ParseOptions{ParseSQL, ParseGDA} {
+			_, _, err := BaseContext.SetStringOptions(&d, s, opts)
+			if (err == nil) != sqlNumberRE.MatchString(s) {
+				t.Errorf("%b: %q: expected match %v, got %v", opts, s, sqlNumberRE.MatchString(s), err)
+			}
+		}
+		if len(s) < 5 {
+			for _, c := range alphabet {
+				check(s + string(c))
+			}
+		}
+	}
+	check("")
+}
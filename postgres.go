@@ -0,0 +1,202 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The PostgreSQL NUMERIC binary format, as produced by numeric_send, is a
+// header of four big-endian 16-bit fields followed by ndigits base-10000
+// digits, also 16 bits each:
+//
+//	ndigits: number of base-10000 digits
+//	weight:  power of 10000 of the first digit
+//	sign:    pgNumericPos, pgNumericNeg, or a special value
+//	dscale:  number of decimal digits after the decimal point
+//
+// Leading and trailing zero digits are not sent, so zero has no digits.
+const (
+	pgNumericPos  = 0x0000
+	pgNumericNeg  = 0x4000
+	pgNumericNaN  = 0xC000
+	pgNumericPInf = 0xD000
+	pgNumericNInf = 0xF000
+
+	pgNumericDScaleMask = 0x3FFF
+	pgNumericDigits     = 4
+	pgNumericBase       = 10000
+)
+
+var errPostgresShort = errors.New("postgres numeric: too short")
+
+// AppendPostgresBinary appends d to buf in the PostgreSQL NUMERIC binary
+// format and returns the extended buffer. Signaling NaNs are encoded as NaN,
+// and the sign of zero and NaN is not preserved since PostgreSQL has neither
+// negative zero nor negative NaN. An error is returned if d is out of the
+// range of the format.
+func (d *Decimal) AppendPostgresBinary(buf []byte) ([]byte, error) {
+	switch d.Form {
+	case NaN, NaNSignaling:
+		return appendPostgresHeader(buf, 0, 0, pgNumericNaN, 0), nil
+	case Infinite:
+		if d.Negative {
+			return appendPostgresHeader(buf, 0, 0, pgNumericNInf, 0), nil
+		}
+		return appendPostgresHeader(buf, 0, 0, pgNumericPInf, 0), nil
+	}
+	var dscale int64
+	if d.Exponent < 0 {
+		dscale = -int64(d.Exponent)
+	}
+	if dscale > pgNumericDScaleMask {
+		return nil, fmt.Errorf("postgres numeric: scale %d out of range", dscale)
+	}
+	if d.Coeff.Sign() == 0 {
+		return appendPostgresHeader(buf, 0, 0, pgNumericPos, uint16(dscale)), nil
+	}
+
+	// Pad the coefficient with zeros on the right so that the exponent is a
+	// multiple of 4, and on the left so that the digits form whole base-10000
+	// digits.
+	var scratch [40]byte
+	digits := d.Coeff.Append(scratch[:0], 10)
+	exp := int64(d.Exponent)
+	for exp%pgNumericDigits != 0 {
+		digits = append(digits, '0')
+		exp--
+	}
+	if r := len(digits) % pgNumericDigits; r != 0 {
+		digits = append(make([]byte, pgNumericDigits-r, len(digits)+pgNumericDigits-r), digits...)
+		for i := 0; i < pgNumericDigits-r; i++ {
+			digits[i] = '0'
+		}
+	}
+	// Remove trailing zero digits. There are no leading ones.
+	for isZeros(digits[len(digits)-pgNumericDigits:]) {
+		digits = digits[:len(digits)-pgNumericDigits]
+		exp += pgNumericDigits
+	}
+	ndigits := int64(len(digits) / pgNumericDigits)
+	weight := ndigits - 1 + exp/pgNumericDigits
+	if ndigits > math.MaxInt16 || weight < math.MinInt16 || weight > math.MaxInt16 {
+		return nil, fmt.Errorf("postgres numeric: %s out of range", d.String())
+	}
+	sign := uint16(pgNumericPos)
+	if d.Negative {
+		sign = pgNumericNeg
+	}
+	buf = appendPostgresHeader(buf, uint16(ndigits), uint16(weight), sign, uint16(dscale))
+	for i := 0; i < len(digits); i += pgNumericDigits {
+		var digit uint16
+		for _, c := range digits[i : i+pgNumericDigits] {
+			digit = digit*10 + uint16(c-'0')
+		}
+		buf = append(buf, byte(digit>>8), byte(digit))
+	}
+	return buf, nil
+}
+
+func isZeros(b []byte) bool {
+	for _, c := range b {
+		if c != '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func appendPostgresHeader(buf []byte, ndigits, weight, sign, dscale uint16) []byte {
+	return append(buf,
+		byte(ndigits>>8), byte(ndigits),
+		byte(weight>>8), byte(weight),
+		byte(sign>>8), byte(sign),
+		byte(dscale>>8), byte(dscale),
+	)
+}
+
+// SetPostgresBinary sets d to the value of b, which is in the PostgreSQL
+// NUMERIC binary format, and returns d. The exponent of d is set from the
+// display scale, so 1.50 sent by PostgreSQL is decoded as 1.50.
+func (d *Decimal) SetPostgresBinary(b []byte) (*Decimal, error) {
+	if len(b) < 8 {
+		return nil, errPostgresShort
+	}
+	ndigits := int(binary.BigEndian.Uint16(b[0:]))
+	weight := int64(int16(binary.BigEndian.Uint16(b[2:])))
+	sign := binary.BigEndian.Uint16(b[4:])
+	dscale := binary.BigEndian.Uint16(b[6:])
+	b = b[8:]
+	if ndigits > math.MaxInt16 {
+		return nil, fmt.Errorf("postgres numeric: invalid number of digits %d", ndigits)
+	}
+	if len(b) != 2*ndigits {
+		return nil, fmt.Errorf("postgres numeric: expected %d digits, got %d bytes", ndigits, len(b))
+	}
+	switch sign {
+	case pgNumericPos, pgNumericNeg:
+	case pgNumericNaN, pgNumericPInf, pgNumericNInf:
+		if ndigits != 0 {
+			return nil, fmt.Errorf("postgres numeric: unexpected digits for sign %#x", sign)
+		}
+		d.Form = NaN
+		if sign != pgNumericNaN {
+			d.Form = Infinite
+		}
+		d.Negative = sign == pgNumericNInf
+		d.Exponent = 0
+		d.Coeff.SetInt64(0)
+		return d, nil
+	default:
+		return nil, fmt.Errorf("postgres numeric: invalid sign %#x", sign)
+	}
+	if dscale&^pgNumericDScaleMask != 0 {
+		return nil, fmt.Errorf("postgres numeric: invalid scale %d", dscale)
+	}
+
+	// The digits are an integer with exponent 4*(weight-ndigits+1).
+	var coeff, tmp BigInt
+	for i := 0; i < ndigits; i++ {
+		digit := binary.BigEndian.Uint16(b[2*i:])
+		if digit >= pgNumericBase {
+			return nil, fmt.Errorf("postgres numeric: invalid digit %d", digit)
+		}
+		coeff.Mul(&coeff, tmp.SetInt64(pgNumericBase))
+		coeff.Add(&coeff, tmp.SetUint64(uint64(digit)))
+	}
+	exp := pgNumericDigits * (weight - int64(ndigits) + 1)
+	if coeff.Sign() != 0 {
+		if exp < -int64(dscale) {
+			// Digits are only sent up to dscale, except for the zeros
+			// padding the last base-10000 digit.
+			var r BigInt
+			shift := tableExp10(-int64(dscale)-exp, &tmp)
+			coeff.QuoRem(&coeff, shift, &r)
+			if r.Sign() != 0 {
+				return nil, errors.New("postgres numeric: digits beyond scale")
+			}
+		} else if exp > -int64(dscale) {
+			coeff.Mul(&coeff, tableExp10(exp+int64(dscale), &tmp))
+		}
+	}
+	d.Form = Finite
+	d.Negative = sign == pgNumericNeg && coeff.Sign() != 0
+	d.Exponent = -int32(dscale)
+	d.Coeff.Set(&coeff)
+	return d, nil
+}
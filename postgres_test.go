@@ -0,0 +1,130 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// postgresFixtures are the results of SELECT numeric_send(v::numeric).
+var postgresFixtures = []struct {
+	s   string
+	hex string
+}{
+	{s: "0", hex: "0000000000000000"},
+	{s: "0.00", hex: "0000000000000002"},
+	{s: "1", hex: "00010000000000000001"},
+	{s: "-1.5", hex: "000200004000000100011388"},
+	{s: "12345.678", hex: "0003000100000003000109291a7c"},
+	{s: "0.0001", hex: "0001ffff000000040001"},
+	{s: "0.000012", hex: "0001fffe0000000604b0"},
+	{s: "10000", hex: "00010001000000000001"},
+	{s: "100000000", hex: "00010002000000000001"},
+	{s: "-123556700", hex: "0003000240000000000109331a2c"},
+	{s: "3.14159265358979323846", hex: "0006000000000014000305872431" + "0e051efc0f06"},
+	{s: "NaN", hex: "00000000c0000000"},
+	{s: "Infinity", hex: "00000000d0000000"},
+	{s: "-Infinity", hex: "00000000f0000000"},
+}
+
+func TestPostgresBinary(t *testing.T) {
+	for _, tc := range postgresFixtures {
+		t.Run(tc.s, func(t *testing.T) {
+			d := newDecimal(t, testCtx, tc.s)
+			b, err := d.AppendPostgresBinary(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h := hex.EncodeToString(b); h != tc.hex {
+				t.Fatalf("expected %s, got %s", tc.hex, h)
+			}
+			var r Decimal
+			if _, err := r.SetPostgresBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if r.String() != d.String() {
+				t.Fatalf("expected %s, got %s", d, &r)
+			}
+		})
+	}
+}
+
+func TestPostgresBinaryConversion(t *testing.T) {
+	tests := []struct {
+		s   string
+		out string
+	}{
+		// Positive exponents become a zero display scale.
+		{s: "1.2E+7", out: "12000000"},
+		{s: "-0", out: "0"},
+		{s: "-0.00", out: "0.00"},
+		{s: "sNaN", out: "NaN"},
+		{s: "-NaN", out: "NaN"},
+		{s: "1E+131071", out: "1" + strings.Repeat("0", 131071)},
+		{s: "1E-16383", out: "1E-16383"},
+		{s: "1E+131072"},
+		{s: "1E-16384"},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		b, err := d.AppendPostgresBinary(nil)
+		if tc.out == "" {
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		var r Decimal
+		if _, err := r.SetPostgresBinary(b); err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if s := r.Text('f'); s != tc.out && r.String() != tc.out {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.out, &r)
+		}
+	}
+}
+
+func TestPostgresBinaryErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"00000000000000",
+		"0001000000000000",
+		"000100000000000000",
+		"00000000000000000000",
+		"0001000000000000270f00",
+		"00010000000000002710",
+		"00010000c00000000001",
+		"0000000012340000",
+		"000000000000c000",
+		"80000000000000000000",
+		// 0.00015 with a display scale of 4.
+		"0002ffff0000000400011388",
+	}
+	for _, h := range tests {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var d Decimal
+		if _, err := d.SetPostgresBinary(b); err == nil {
+			t.Errorf("%s: expected error, got %s", h, &d)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	bigIntOne  = big.NewInt(1)
+	bigIntFive = big.NewInt(5)
+	bigIntTen  = big.NewInt(10)
+)
+
+// bigIntPow10 returns 10**n.
+func bigIntPow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigIntTen, big.NewInt(n), nil)
+}
+
+// Rat sets z to the exact value of d and returns z. If z is nil, a new
+// big.Rat is allocated. An error is returned if d is not finite, or if it is
+// not zero and the numerator or denominator would have more than
+// maxExactScale digits beyond the coefficient.
+func (d *Decimal) Rat(z *big.Rat) (*big.Rat, error) {
+	if d.Form != Finite {
+		return nil, fmt.Errorf("%s is not finite", d.String())
+	}
+	if z == nil {
+		z = new(big.Rat)
+	}
+	if d.Coeff.Sign() == 0 {
+		return z.SetInt64(0), nil
+	}
+	if d.Exponent > maxExactScale || d.Exponent < -maxExactScale {
+		return nil, fmt.Errorf("%s: %s", d.String(), errExponentOutOfRangeStr)
+	}
+	num := d.Coeff.MathBigInt()
+	if d.Negative {
+		num.Neg(num)
+	}
+	if d.Exponent >= 0 {
+		num.Mul(num, bigIntPow10(int64(d.Exponent)))
+		return z.SetInt(num), nil
+	}
+	return z.SetFrac(num, bigIntPow10(-int64(d.Exponent))), nil
+}
+
+// splitDenom returns den as 2**twos × 5**fives × rest.
+func splitDenom(den *big.Int) (twos, fives int64, rest *big.Int) {
+	twos = int64(den.TrailingZeroBits())
+	rest = new(big.Int).Rsh(den, uint(twos))
+	var q, r big.Int
+	for {
+		q.QuoRem(rest, bigIntFive, &r)
+		if r.Sign() != 0 {
+			return twos, fives, rest
+		}
+		rest.Set(&q)
+		fives++
+	}
+}
+
+// SetRat sets d to x and returns d. An error is returned if x has no finite
+// decimal expansion, that is, if its denominator has prime factors other than
+// 2 and 5.
+func (d *Decimal) SetRat(x *big.Rat) (*Decimal, error) {
+	if _, err := BaseContext.SetRat(d, x); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// SetRat sets d to x rounded to the precision of c. If the denominator of x
+// has no prime factors other than 2 and 5, x has a finite decimal expansion
+// and the result is exact unless it has more digits than the precision.
+// Otherwise the result is rounded, and c must have a non-zero precision.
+func (c *Context) SetRat(d *Decimal, x *big.Rat) (Condition, error) {
+	num := x.Num()
+	twos, fives, rest := splitDenom(x.Denom())
+	if rest.Cmp(bigIntOne) != 0 {
+		if c.Precision == 0 {
+			return 0, fmt.Errorf("%s has no finite decimal expansion", x.RatString())
+		}
+		var n, m Decimal
+		n.Coeff.SetMathBigInt(num)
+		n.Coeff.Abs(&n.Coeff)
+		n.Negative = num.Sign() < 0
+		m.Coeff.SetMathBigInt(x.Denom())
+		return c.Quo(d, &n, &m)
+	}
+	// num / (2**twos × 5**fives) is num × 2**(k-twos) × 5**(k-fives) / 10**k.
+	k := twos
+	if fives > k {
+		k = fives
+	}
+	coeff := new(big.Int).Abs(num)
+	coeff.Lsh(coeff, uint(k-twos))
+	coeff.Mul(coeff, new(big.Int).Exp(bigIntFive, big.NewInt(k-fives), nil))
+	d.Form = Finite
+	d.Negative = num.Sign() < 0
+	d.Exponent = 0
+	d.Coeff.SetMathBigInt(coeff)
+	res := d.setExponent(c, unknownNumDigits, 0, -k)
+	res |= c.round(d, d)
+	return c.goError(res)
+}
+
+// RepeatingExpansion returns the decimal expansion of x as a terminating
+// prefix and a repeating part: x is prefix followed by the digits of repetend
+// repeated forever, starting at the digit after the last digit of prefix. For
+// example, 1/6 is 0.1666..., so its prefix is 0.1 and its repetend "6". The
+// repetend is empty if x has a finite expansion. The sign of x is the sign of
+// prefix, which is -0 for values such as -1/3.
+//
+// The repetend of p/q can have up to q-1 digits. An error is returned if it
+// has more than maxPeriod digits.
+func RepeatingExpansion(x *big.Rat, maxPeriod int) (prefix *Decimal, repetend string, err error) {
+	num := new(big.Int).Abs(x.Num())
+	den := x.Denom()
+	twos, fives, rest := splitDenom(den)
+	// The repeating part starts after k fractional digits.
+	k := twos
+	if fives > k {
+		k = fives
+	}
+	if k > -math.MinInt32 {
+		return nil, "", fmt.Errorf("%s: %s", x.RatString(), errExponentOutOfRangeStr)
+	}
+	var p, r big.Int
+	p.QuoRem(num.Mul(num, bigIntPow10(k)), den, &r)
+	prefix = &Decimal{Negative: x.Sign() < 0, Exponent: int32(-k)}
+	prefix.Coeff.SetMathBigInt(&p)
+	if rest.Cmp(bigIntOne) == 0 {
+		return prefix, "", nil
+	}
+	// Long division until the remainder repeats. Since 10 is coprime with
+	// the rest of the denominator, the first remainder is the one repeated.
+	var digits []byte
+	start := new(big.Int).Set(&r)
+	var q big.Int
+	for {
+		if len(digits) == maxPeriod {
+			return nil, "", fmt.Errorf("%s repeats with a period of more than %d digits", x.RatString(), maxPeriod)
+		}
+		r.Mul(&r, bigIntTen)
+		q.QuoRem(&r, den, &r)
+		digits = append(digits, byte('0'+q.Int64()))
+		if r.Cmp(start) == 0 {
+			return prefix, string(digits), nil
+		}
+	}
+}
+
+// FormatRepeating returns the decimal expansion of x, with its repeating part
+// in parentheses, as in "0.(142857)" for 1/7 and "-0.1(6)" for -1/6. Values
+// with a finite expansion are formatted without parentheses. An error is
+// returned if the repeating part has more than maxPeriod digits.
+func FormatRepeating(x *big.Rat, maxPeriod int) (string, error) {
+	prefix, repetend, err := RepeatingExpansion(x, maxPeriod)
+	if err != nil {
+		return "", err
+	}
+	buf := prefix.Append(nil, 'f')
+	if repetend == "" {
+		return string(buf), nil
+	}
+	if prefix.Exponent == 0 {
+		buf = append(buf, '.')
+	}
+	buf = append(buf, '(')
+	buf = append(buf, repetend...)
+	return string(append(buf, ')')), nil
+}
+
+// ParseRepeating returns the value of s, which is a decimal with an optional
+// repeating part in parentheses after its decimal point, as produced by
+// FormatRepeating: "0.(142857)", "-1.1(6)" and ".(3)" are valid. Exponents and
+// special values are not allowed.
+func ParseRepeating(s string) (*big.Rat, error) {
+	const opts = ParsePlus | ParseLeadingDot | ParseTrailingDot | ParseLeadingZeros
+	open := strings.IndexByte(s, '(')
+	prefixText := s
+	if open >= 0 {
+		prefixText = s[:open]
+		// Allow a prefix without digits, as in ".(3)".
+		if strings.TrimLeft(prefixText, "+-") == "." {
+			prefixText = prefixText[:len(prefixText)-1] + "0."
+		}
+	}
+	var prefix Decimal
+	if _, _, err := BaseContext.SetStringOptions(&prefix, prefixText, opts); err != nil {
+		if pe, ok := err.(*ParseError); ok {
+			pe.Input = s
+		}
+		return nil, err
+	}
+	z, err := prefix.Rat(nil)
+	if err != nil {
+		return nil, err
+	}
+	if open < 0 {
+		return z, nil
+	}
+	if strings.IndexByte(prefixText, '.') < 0 {
+		return nil, newParseError(s, open, "repeating part before decimal point")
+	}
+	end := strings.IndexByte(s, ')')
+	if end < 0 {
+		return nil, newParseError(s, len(s), "missing ')'")
+	}
+	if end != len(s)-1 {
+		return nil, newParseError(s, end+1, "unexpected characters after ')'")
+	}
+	repetend := s[open+1 : end]
+	if repetend == "" {
+		return nil, newParseError(s, end, "empty repeating part")
+	}
+	for i := 0; i < len(repetend); i++ {
+		if !isDigit(repetend[i]) {
+			return nil, newParseError(s, open+1+i, "invalid digit in repeating part")
+		}
+	}
+	// The repeating part 0.000(r) with f zeros is r / (10**f × (10**n - 1)).
+	var r big.Int
+	r.SetString(repetend, 10)
+	den := bigIntPow10(int64(len(repetend)))
+	den.Sub(den, bigIntOne)
+	den.Mul(den, bigIntPow10(-int64(prefix.Exponent)))
+	rep := new(big.Rat).SetFrac(&r, den)
+	if prefix.Negative {
+		rep.Neg(rep)
+	}
+	return z.Add(z, rep), nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func newRat(t *testing.T, s string) *big.Rat {
+	t.Helper()
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		t.Fatalf("invalid rat %s", s)
+	}
+	return r
+}
+
+func TestDecimalRat(t *testing.T) {
+	tests := []struct {
+		s   string
+		rat string
+	}{
+		{s: "0", rat: "0"},
+		{s: "-0", rat: "0"},
+		{s: "0E+900000000", rat: "0"},
+		{s: "1.5", rat: "3/2"},
+		{s: "-0.125", rat: "-1/8"},
+		{s: "1.20", rat: "6/5"},
+		{s: "1.2E+3", rat: "1200"},
+		{s: "-1E-30", rat: "-1/1000000000000000000000000000000"},
+		{s: "123456789012345678901234567890.5", rat: "246913578024691357802469135781/2"},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		r, err := d.Rat(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(newRat(t, tc.rat)) != 0 {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.rat, r.RatString())
+		}
+		// Round trip through SetRat, which can't preserve trailing zeros or
+		// the sign of zero.
+		var back Decimal
+		if _, err := back.SetRat(r); err != nil {
+			t.Fatal(err)
+		}
+		if back.Cmp(d) != 0 {
+			t.Fatalf("%s: round trip got %s", tc.s, &back)
+		}
+	}
+	for _, s := range []string{"NaN", "Inf", "-Inf", "1E+900000000", "-1E-900000000"} {
+		if _, err := newDecimal(t, testCtx, s).Rat(nil); err == nil {
+			t.Fatalf("%s: expected error", s)
+		}
+	}
+	// z is reused.
+	z := new(big.Rat)
+	if r, _ := New(5, -1).Rat(z); r != z || z.RatString() != "1/2" {
+		t.Fatalf("unexpected %s", z)
+	}
+}
+
+func TestContextSetRat(t *testing.T) {
+	tests := []struct {
+		rat       string
+		precision uint32
+		expected  string
+		res       Condition
+		err       bool
+	}{
+		{rat: "1/8", expected: "0.125"},
+		{rat: "-3/40", expected: "-0.075"},
+		{rat: "5/2", expected: "2.5"},
+		{rat: "0", expected: "0"},
+		{rat: "100", expected: "100"},
+		{rat: "1/1024", expected: "0.0009765625"},
+		{rat: "1/1024", precision: 10, expected: "0.0009765625"},
+		{rat: "1/1024", precision: 3, expected: "0.000977", res: Inexact | Rounded},
+		{rat: "7/1", precision: 3, expected: "7"},
+		{rat: "1/3", err: true},
+		{rat: "1/3", precision: 10, expected: "0.3333333333", res: Inexact | Rounded},
+		{rat: "-2/3", precision: 5, expected: "-0.66667", res: Inexact | Rounded},
+		{rat: "22/7", precision: 20, expected: "3.1428571428571428571", res: Inexact | Rounded},
+	}
+	for _, tc := range tests {
+		c := BaseContext.WithPrecision(tc.precision)
+		var d Decimal
+		res, err := c.SetRat(&d, newRat(t, tc.rat))
+		if tc.err {
+			if err == nil {
+				t.Fatalf("%s: expected error, got %s", tc.rat, &d)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.rat, err)
+		}
+		if d.String() != tc.expected || res != tc.res {
+			t.Fatalf("%s, precision %d: expected %s %s, got %s %s", tc.rat, tc.precision, tc.expected, tc.res, &d, res)
+		}
+	}
+	if _, err := new(Decimal).SetRat(newRat(t, "1/3")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRepeating(t *testing.T) {
+	tests := []struct {
+		rat string
+		s   string
+	}{
+		{rat: "1/7", s: "0.(142857)"},
+		{rat: "22/7", s: "3.(142857)"},
+		{rat: "1/3", s: "0.(3)"},
+		{rat: "-1/3", s: "-0.(3)"},
+		{rat: "1/6", s: "0.1(6)"},
+		{rat: "-7/6", s: "-1.1(6)"},
+		{rat: "1/12", s: "0.08(3)"},
+		{rat: "7/12", s: "0.58(3)"},
+		{rat: "1/81", s: "0.(012345679)"},
+		{rat: "1/11", s: "0.(09)"},
+		{rat: "1000/3", s: "333.(3)"},
+		{rat: "1/8", s: "0.125"},
+		{rat: "-5/2", s: "-2.5"},
+		{rat: "42", s: "42"},
+		{rat: "0", s: "0"},
+	}
+	for _, tc := range tests {
+		x := newRat(t, tc.rat)
+		s, err := FormatRepeating(x, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != tc.s {
+			t.Fatalf("%s: expected %s, got %s", tc.rat, tc.s, s)
+		}
+		r, err := ParseRepeating(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(x) != 0 {
+			t.Fatalf("%s: expected %s, got %s", s, tc.rat, r.RatString())
+		}
+	}
+
+	prefix, repetend, err := RepeatingExpansion(newRat(t, "1/97"), 96)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prefix.IsZero() || len(repetend) != 96 || !strings.HasPrefix(repetend, "010309278350515463917") {
+		t.Fatalf("unexpected expansion of 1/97: %s %s", prefix, repetend)
+	}
+	if _, _, err := RepeatingExpansion(newRat(t, "1/97"), 95); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Random fractions round trip.
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		x := big.NewRat(rnd.Int63n(2000000)-1000000, rnd.Int63n(1000)+1)
+		s, err := FormatRepeating(x, 1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := ParseRepeating(s)
+		if err != nil {
+			t.Fatalf("%s: %v", s, err)
+		}
+		if r.Cmp(x) != 0 {
+			t.Fatalf("%s: expected %s, got %s", s, x.RatString(), r.RatString())
+		}
+	}
+}
+
+func TestParseRepeating(t *testing.T) {
+	tests := []struct {
+		s   string
+		rat string
+	}{
+		{s: ".(3)", rat: "1/3"},
+		{s: "-.(3)", rat: "-1/3"},
+		{s: "+0.(3)", rat: "1/3"},
+		{s: "0.(9)", rat: "1"},
+		{s: "1.(0)", rat: "1"},
+		{s: "0.1(6)", rat: "1/6"},
+		{s: "0.1(66)", rat: "1/6"},
+		{s: "1.", rat: "1"},
+		{s: "-1.25", rat: "-5/4"},
+	}
+	for _, tc := range tests {
+		r, err := ParseRepeating(tc.s)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if r.Cmp(newRat(t, tc.rat)) != 0 {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.rat, r.RatString())
+		}
+	}
+	for _, s := range []string{
+		"", "(3)", "1(3)", "0.(", "0.()", "0.(3", "0.(1a)", "0.(3)4", "0.(3))",
+		"1e2", "1e2.(3)", "NaN", "Inf", "0.(+3)",
+	} {
+		_, err := ParseRepeating(s)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("%q: expected ParseError, got %v", s, err)
+		}
+		if pe.Input != s {
+			t.Fatalf("%q: unexpected input %q", s, pe.Input)
+		}
+	}
+}
@@ -61,7 +61,7 @@ func (r Rounder) ShouldAddOne(result *BigInt, neg bool, half int) bool {
 // Round sets d to rounded x.
 func (r Rounder) Round(c *Context, d, x *Decimal, disableIfPrecisionZero bool) Condition {
 	d.Set(x)
-	nd := x.NumDigits()
+	nd := c.numDigits(&x.Coeff)
 	xs := x.Sign()
 	var res Condition
 
@@ -88,19 +88,22 @@ func (r Rounder) Round(c *Context, d, x *Decimal, disableIfPrecisionZero bool) C
 			return SystemUnderflow | Underflow
 		}
 		res |= Rounded
-		var y, m BigInt
-		e := tableExp10(diff, &y)
-		y.QuoRem(&d.Coeff, e, &m)
+		var ly, lm, tmpE BigInt
+		y, m := &ly, &lm
+		if w := c.Workspace; w != nil {
+			y, m = &w.round.y, &w.round.m
+		}
+		e := c.tableExp10(diff, &tmpE)
+		y.QuoRem(&d.Coeff, e, m)
 		if m.Sign() != 0 {
 			res |= Inexact
-			var discard Decimal
-			discard.Coeff.Set(&m)
-			discard.Exponent = int32(-diff)
-			if r.ShouldAddOne(&y, x.Negative, discard.Cmp(decimalHalf)) {
-				roundAddOne(&y, &diff)
+			// Compare the discarded digits, m/e, with one half.
+			m.Lsh(m, 1)
+			if r.ShouldAddOne(y, x.Negative, m.Cmp(e)) {
+				roundAddOne(y, &diff)
 			}
 		}
-		d.Coeff.Set(&y)
+		d.Coeff.Set(y)
 		// The coefficient changed, so recompute num digits in setExponent.
 		nd = unknownNumDigits
 	} else {
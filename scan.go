@@ -0,0 +1,110 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// FmtScanner adapts a Decimal to the fmt.Scanner interface, which Decimal
+// can't implement itself because its Scan method implements the
+// database/sql.Scanner interface:
+//
+//	var d Decimal
+//	_, err := fmt.Sscan("1.5", FmtScanner{&d})
+type FmtScanner struct {
+	Decimal *Decimal
+}
+
+// Scan implements the fmt.Scanner interface. It accepts the verbs %v, %s, %d,
+// %e, %E, %f, %F, %g and %G, and reads a token of the characters that can
+// appear in a decimal: ASCII letters and digits, '.', '+' and '-'.
+func (s FmtScanner) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'v', 's', 'd', 'e', 'E', 'f', 'F', 'g', 'G':
+	default:
+		return fmt.Errorf("bad verb %%%c for Decimal", verb)
+	}
+	tok, err := state.Token(true, isDecimalRune)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.Decimal.SetStringBytes(tok)
+	return err
+}
+
+func isDecimalRune(r rune) bool {
+	switch {
+	case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return true
+	}
+	return r == '.' || r == '+' || r == '-'
+}
+
+// Decoder reads decimals separated by white space from an input stream.
+type Decoder struct {
+	s *bufio.Scanner
+	// offset is the stream offset of the data passed to the next split call,
+	// and start the stream offset of the last token.
+	offset, start int64
+}
+
+// NewDecoder returns a new Decoder that reads from r. Decimals are limited to
+// bufio.MaxScanTokenSize bytes unless a larger buffer is set with Buffer.
+func NewDecoder(r io.Reader) *Decoder {
+	dec := &Decoder{s: bufio.NewScanner(r)}
+	dec.s.Split(dec.split)
+	return dec
+}
+
+// split wraps bufio.ScanWords to track the stream offset of each token.
+func (dec *Decoder) split(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanWords(data, atEOF)
+	if token != nil {
+		// token is a subslice of data.
+		dec.start = dec.offset + int64(cap(data)-cap(token))
+	}
+	dec.offset += int64(advance)
+	return advance, token, err
+}
+
+// Buffer sets the initial buffer and the maximum size of a decimal, as in
+// (*bufio.Scanner).Buffer. It must be called before the first call to Decode.
+func (dec *Decoder) Buffer(buf []byte, max int) {
+	dec.s.Buffer(buf, max)
+}
+
+// Decode sets d to the next decimal of the input. At the end of the input it
+// returns io.EOF. If the next decimal is invalid, Decode returns a *ParseError
+// for its token, and subsequent calls continue with the following decimal. Add
+// InputOffset to the error's Offset to find the failure in the stream. Decode
+// does not allocate for values with at most 19 coefficient digits.
+func (dec *Decoder) Decode(d *Decimal) error {
+	if !dec.s.Scan() {
+		if err := dec.s.Err(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	_, _, err := d.SetStringBytes(dec.s.Bytes())
+	return err
+}
+
+// InputOffset returns the stream offset of the last decimal read by Decode.
+func (dec *Decoder) InputOffset() int64 {
+	return dec.start
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFmtScanner(t *testing.T) {
+	var a, b, c Decimal
+	var s string
+	n, err := fmt.Sscan("  1.50 -2E+3\tNaN rest", FmtScanner{&a}, FmtScanner{&b}, FmtScanner{&c}, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 || a.String() != "1.50" || b.String() != "-2E+3" || c.String() != "NaN" || s != "rest" {
+		t.Fatalf("unexpected scan: %d %s %s %s %q", n, &a, &b, &c, s)
+	}
+
+	if _, err := fmt.Sscanf("1.5,-0.25;", "%f,%g;", FmtScanner{&a}, FmtScanner{&b}); err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != "1.5" || b.String() != "-0.25" {
+		t.Fatalf("unexpected scan: %s %s", &a, &b)
+	}
+
+	if _, err := fmt.Sscanf("1", "%x", FmtScanner{&a}); err == nil {
+		t.Fatal("expected error for bad verb")
+	}
+	_, err = fmt.Sscan("1.2.3", FmtScanner{&a})
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Offset != 3 {
+		t.Fatalf("expected ParseError at offset 3, got %v", err)
+	}
+	if _, err := fmt.Sscan("", FmtScanner{&a}); err == nil {
+		t.Fatal("expected error for empty input")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	const input = "1.5 -2E+3\n\n  NaN 1x2 12345678901234567890123.5\t-0 "
+	dec := NewDecoder(strings.NewReader(input))
+	for _, tc := range []struct {
+		expected string
+		offset   int64
+		err      bool
+		errAt    int
+	}{
+		{expected: "1.5", offset: 0},
+		{expected: "-2E+3", offset: 4},
+		{expected: "NaN", offset: 13},
+		{offset: 17, err: true, errAt: 1},
+		{expected: "12345678901234567890123.5", offset: 21},
+		{expected: "-0", offset: 47},
+	} {
+		var d Decimal
+		err := dec.Decode(&d)
+		if off := dec.InputOffset(); off != tc.offset {
+			t.Fatalf("expected offset %d, got %d", tc.offset, off)
+		}
+		if tc.err {
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Offset != tc.errAt {
+				t.Fatalf("expected ParseError at offset %d, got %v", tc.errAt, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.String() != tc.expected {
+			t.Fatalf("expected %s, got %s", tc.expected, &d)
+		}
+	}
+	var d Decimal
+	if err := dec.Decode(&d); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestDecoderBuffer(t *testing.T) {
+	long := "1" + strings.Repeat("0", 100000)
+	dec := NewDecoder(strings.NewReader(long))
+	var d Decimal
+	if err := dec.Decode(&d); err == nil {
+		t.Fatal("expected error for a decimal larger than the buffer")
+	}
+	dec = NewDecoder(strings.NewReader(long))
+	dec.Buffer(nil, len(long)+1)
+	if err := dec.Decode(&d); err != nil {
+		t.Fatal(err)
+	}
+	if d.NumDigits() != int64(len(long)) {
+		t.Fatalf("expected %d digits, got %d", len(long), d.NumDigits())
+	}
+}
+
+func TestDecoderAllocs(t *testing.T) {
+	input := strings.Repeat("-1234.5678 1E+5 0.001\n", 1000)
+	r := strings.NewReader(input)
+	dec := NewDecoder(r)
+	dec.Buffer(make([]byte, 0, 4096), 4096)
+	var d Decimal
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := dec.Decode(&d); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %.1f", allocs)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Unscaled bytes
+//
+// Avro and Parquet decimals, and Java's BigDecimal.unscaledValue().toByteArray(),
+// represent a value as an unscaled integer U and a scale S stored elsewhere,
+// with value U × 10**-S. U is written as a big-endian two's-complement integer,
+// either in the fewest bytes that hold it and its sign bit (Avro bytes,
+// Parquet BYTE_ARRAY) or sign-extended to a fixed length (Avro fixed, Parquet
+// FIXED_LEN_BYTE_ARRAY).
+
+// AppendUnscaledBytes appends to buf the unscaled value of d at the given
+// scale as a minimal-length big-endian two's-complement integer, and returns
+// the extended buffer. Zero is encoded as a single zero byte. An error is
+// returned if d is not finite or cannot be represented exactly with the scale.
+func (d *Decimal) AppendUnscaledBytes(buf []byte, scale int32) ([]byte, error) {
+	var u Decimal
+	if err := d.unscaled(&u, scale); err != nil {
+		return nil, err
+	}
+	return appendTwosComplement(buf, &u.Coeff, u.Negative), nil
+}
+
+// AppendUnscaledBytesFixed is like AppendUnscaledBytes, but sign-extends the
+// integer to exactly size bytes. An error is returned if it does not fit.
+func (d *Decimal) AppendUnscaledBytesFixed(buf []byte, scale int32, size int) ([]byte, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("invalid unscaled byte size %d", size)
+	}
+	if d.Form == Finite && !d.IsZero() {
+		// An integer of n bytes has at most 8n*log10(2)+1 digits. Rejecting
+		// larger values first avoids computing large unscaled values.
+		if int64(d.NumDigits())+int64(d.Exponent)+int64(scale) > int64(size)*8*30103/100000+1 {
+			return nil, fmt.Errorf("%s with scale %d overflows %d bytes", d.String(), scale, size)
+		}
+	}
+	var u Decimal
+	if err := d.unscaled(&u, scale); err != nil {
+		return nil, err
+	}
+	start := len(buf)
+	buf = appendTwosComplement(buf, &u.Coeff, u.Negative)
+	n := len(buf) - start
+	if n > size {
+		return nil, fmt.Errorf("%s with scale %d overflows %d bytes", d.String(), scale, size)
+	}
+	// Sign-extend by prepending the sign byte.
+	ext := byte(0)
+	if u.Negative {
+		ext = 0xff
+	}
+	for i := n; i < size; i++ {
+		buf = append(buf, 0)
+	}
+	copy(buf[start+size-n:], buf[start:start+n])
+	for i := start; i < start+size-n; i++ {
+		buf[i] = ext
+	}
+	return buf, nil
+}
+
+// unscaled sets u to d quantized to the exponent -scale.
+func (d *Decimal) unscaled(u *Decimal, scale int32) error {
+	if d.Form != Finite {
+		return fmt.Errorf("%s is not finite", d.String())
+	}
+	if scale < -MaxExponent || scale > -MinExponent {
+		return fmt.Errorf("scale %d out of range", scale)
+	}
+	if diff := -int64(scale) - int64(d.Exponent); diff < MinExponent || diff > MaxExponent {
+		if !d.IsZero() {
+			return fmt.Errorf("%s cannot be represented with scale %d", d.String(), scale)
+		}
+	}
+	if res := BaseContext.quantize(u, d, -scale); res&^Rounded != 0 {
+		return fmt.Errorf("%s cannot be represented with scale %d", d.String(), scale)
+	}
+	u.Negative = d.Negative && !u.IsZero()
+	return nil
+}
+
+// appendTwosComplement appends the minimal big-endian two's-complement
+// encoding of the integer with magnitude m and the given sign.
+func appendTwosComplement(buf []byte, m *BigInt, neg bool) []byte {
+	if !neg {
+		n := m.BitLen()/8 + 1
+		start := len(buf)
+		for i := 0; i < n; i++ {
+			buf = append(buf, 0)
+		}
+		m.FillBytes(buf[start:])
+		return buf
+	}
+	// -m in two's complement is the complement of m-1.
+	var m1 BigInt
+	m1.Sub(m, bigOne)
+	n := m1.BitLen()/8 + 1
+	start := len(buf)
+	for i := 0; i < n; i++ {
+		buf = append(buf, 0)
+	}
+	m1.FillBytes(buf[start:])
+	for i := start; i < len(buf); i++ {
+		buf[i] = ^buf[i]
+	}
+	return buf
+}
+
+// SetUnscaledBytes sets d to the value of the big-endian two's-complement
+// unscaled integer b at the given scale, and returns d. It decodes both
+// minimal and sign-extended fixed-length encodings. The exponent of d is
+// -scale.
+func (d *Decimal) SetUnscaledBytes(b []byte, scale int32) (*Decimal, error) {
+	if len(b) == 0 {
+		return nil, errors.New("empty unscaled bytes")
+	}
+	if scale < -MaxExponent || scale > -MinExponent {
+		return nil, fmt.Errorf("scale %d out of range", scale)
+	}
+	neg := b[0]&0x80 != 0
+	if neg {
+		// The magnitude is the complement of b, plus one.
+		tmp := make([]byte, len(b))
+		for i, c := range b {
+			tmp[i] = ^c
+		}
+		d.Coeff.SetBytes(tmp)
+		d.Coeff.Add(&d.Coeff, bigOne)
+	} else {
+		d.Coeff.SetBytes(b)
+	}
+	d.Form = Finite
+	d.Negative = neg
+	d.Exponent = -scale
+	return d, nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestUnscaledBytes(t *testing.T) {
+	// The expected bytes match BigInteger.toByteArray in Java.
+	tests := []struct {
+		s     string
+		scale int32
+		hex   string
+		out   string
+	}{
+		{s: "0", scale: 0, hex: "00"},
+		{s: "-0.00", scale: 2, hex: "00", out: "0.00"},
+		{s: "1", scale: 0, hex: "01"},
+		{s: "127", scale: 0, hex: "7f"},
+		{s: "128", scale: 0, hex: "0080"},
+		{s: "255", scale: 0, hex: "00ff"},
+		{s: "-1", scale: 0, hex: "ff"},
+		{s: "-128", scale: 0, hex: "80"},
+		{s: "-129", scale: 0, hex: "ff7f"},
+		{s: "-256", scale: 0, hex: "ff00"},
+		{s: "123.45", scale: 2, hex: "3039"},
+		{s: "-1.23", scale: 2, hex: "85"},
+		{s: "1.5", scale: 3, hex: "05dc", out: "1.500"},
+		{s: "1.2E+3", scale: -2, hex: "0c"},
+		{s: "-18446744073709551616", scale: 0, hex: "ff0000000000000000"},
+		{s: "18446744073709551616", scale: 0, hex: "010000000000000000"},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		b, err := d.AppendUnscaledBytes([]byte("x"), tc.scale)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if h := hex.EncodeToString(b[1:]); h != tc.hex {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.hex, h)
+		}
+		var r Decimal
+		if _, err := r.SetUnscaledBytes(b[1:], tc.scale); err != nil {
+			t.Fatal(err)
+		}
+		out := tc.out
+		if out == "" {
+			out = tc.s
+		}
+		if r.String() != out {
+			t.Fatalf("%s: expected %s, got %s", tc.s, out, &r)
+		}
+	}
+}
+
+func TestUnscaledBytesFixed(t *testing.T) {
+	tests := []struct {
+		s     string
+		scale int32
+		size  int
+		hex   string
+	}{
+		{s: "0", scale: 2, size: 4, hex: "00000000"},
+		{s: "1.23", scale: 2, size: 4, hex: "0000007b"},
+		{s: "-1.23", scale: 2, size: 4, hex: "ffffff85"},
+		{s: "-1", scale: 0, size: 16, hex: "ffffffffffffffffffffffffffffffff"},
+		{s: "127", scale: 0, size: 1, hex: "7f"},
+		{s: "-128", scale: 0, size: 1, hex: "80"},
+		{s: "128", scale: 0, size: 1},
+		{s: "-129", scale: 0, size: 1},
+		{s: "1.27", scale: 2, size: 1, hex: "7f"},
+		{s: "1.28", scale: 2, size: 1},
+		{s: "99999999999999999999999999999999999999", scale: 0, size: 16, hex: "4b3b4ca85a86c47a098a223fffffffff"},
+		{s: "1E+38", scale: 0, size: 16, hex: "4b3b4ca85a86c47a098a224000000000"},
+		{s: "2E+38", scale: 0, size: 16},
+		{s: "1E+100000", scale: 0, size: 16},
+		{s: "1", scale: 0, size: 0},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		b, err := d.AppendUnscaledBytesFixed([]byte("x"), tc.scale, tc.size)
+		if tc.hex == "" {
+			if err == nil {
+				t.Fatalf("%s in %d bytes: expected error, got %x", tc.s, tc.size, b)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.s, err)
+		}
+		if h := hex.EncodeToString(b[1:]); h != tc.hex {
+			t.Fatalf("%s: expected %s, got %s", tc.s, tc.hex, h)
+		}
+		var r Decimal
+		if _, err := r.SetUnscaledBytes(b[1:], tc.scale); err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(d) != 0 {
+			t.Fatalf("%s: got %s", tc.s, &r)
+		}
+	}
+}
+
+func TestUnscaledBytesErrors(t *testing.T) {
+	tests := []struct {
+		s     string
+		scale int32
+	}{
+		{s: "1.234", scale: 2},
+		{s: "1E-100000", scale: 0},
+		{s: "1", scale: MaxExponent + 1},
+		{s: "1", scale: -MaxExponent - 1},
+		{s: "NaN", scale: 0},
+		{s: "Inf", scale: 0},
+	}
+	for _, tc := range tests {
+		d := newDecimal(t, testCtx, tc.s)
+		if b, err := d.AppendUnscaledBytes(nil, tc.scale); err == nil {
+			t.Errorf("%s scale %d: expected error, got %x", tc.s, tc.scale, b)
+		}
+	}
+	var d Decimal
+	if _, err := d.SetUnscaledBytes(nil, 0); err == nil {
+		t.Error("expected error for empty bytes")
+	}
+	if _, err := d.SetUnscaledBytes([]byte{1}, MaxExponent+1); err == nil {
+		t.Error("expected error for scale")
+	}
+}
+
+// TestUnscaledBytesRandom compares the encoding against a two's-complement
+// encoding computed with math/big.
+func TestUnscaledBytesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 5000; i++ {
+		var v big.Int
+		v.Rand(rng, new(big.Int).Lsh(big.NewInt(1), uint(rng.Intn(200)+1)))
+		if rng.Intn(2) == 0 {
+			v.Neg(&v)
+		}
+		scale := int32(rng.Intn(20))
+		d := NewWithBigInt(new(BigInt).SetMathBigInt(&v), -scale)
+
+		// Find the smallest n such that v fits in n bytes, and add 2**8n to
+		// negative values.
+		n := 1
+		for lim := big.NewInt(128); ; n++ {
+			if v.Cmp(lim) < 0 && v.Cmp(new(big.Int).Neg(lim)) >= 0 {
+				break
+			}
+			lim.Lsh(lim, 8)
+		}
+		var u big.Int
+		u.Set(&v)
+		if v.Sign() < 0 {
+			u.Add(&u, new(big.Int).Lsh(big.NewInt(1), uint(8*n)))
+		}
+		expected := u.FillBytes(make([]byte, n))
+
+		b, err := d.AppendUnscaledBytes(nil, scale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprintf("%x", b) != fmt.Sprintf("%x", expected) {
+			t.Fatalf("%s: expected %x, got %x", &v, expected, b)
+		}
+		var r Decimal
+		if _, err := r.SetUnscaledBytes(b, scale); err != nil {
+			t.Fatal(err)
+		}
+		if r.CmpTotal(d) != 0 {
+			t.Fatalf("expected %s, got %s", d, &r)
+		}
+	}
+}
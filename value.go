@@ -0,0 +1,249 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+// Value is an immutable decimal with value semantics. It is a companion to
+// Decimal for code that prefers to write expressions such as a.Add(b).Mul(c)
+// instead of using the destination-style methods of Context. Methods never
+// modify their receiver or arguments; they return a new Value instead, so
+// Values can be freely copied and shared.
+//
+// Each Value carries the Context used for its arithmetic. Operations with
+// two operands use the Context of the receiver. The zero value is 0 and uses
+// ValueContext.
+//
+// Like ErrDecimal, errors are sticky: once an operation fails, the error is
+// carried by every Value derived from the result and reported by Err. The
+// Conditions raised along the way are accumulated and reported by Flags.
+type Value struct {
+	// d is never modified after the Value is constructed. Copies of a Value
+	// may thus share the big.Int backing d.Coeff.
+	d     Decimal
+	ctx   *Context
+	flags Condition
+	err   error
+}
+
+// ValueContext is the Context used by Values that do not have one attached.
+// It rounds to 34 digits, the precision of an IEEE 754 decimal128. Should
+// not be mutated.
+var ValueContext = Context{
+	Precision:   34,
+	MaxExponent: MaxExponent,
+	MinExponent: MinExponent,
+	Traps:       DefaultTraps,
+}
+
+// NewValue returns a Value with the given coefficient and exponent.
+func NewValue(coeff int64, exponent int32) Value {
+	var v Value
+	v.d.SetFinite(coeff, exponent)
+	return v
+}
+
+// NewValueFromString returns a Value parsed from s. Like
+// (*Decimal).SetString, it has no restrictions on exponents or precision. If s
+// cannot be parsed, the error is reported by the Err method of the result.
+func NewValueFromString(s string) Value {
+	var v Value
+	_, v.flags, v.err = v.d.SetString(s)
+	return v
+}
+
+// NewValueFromDecimal returns a Value holding a copy of d.
+func NewValueFromDecimal(d *Decimal) Value {
+	var v Value
+	v.d.Set(d)
+	return v
+}
+
+// WithContext returns a copy of v that uses c for its arithmetic.
+func (v Value) WithContext(c *Context) Value {
+	v.ctx = c
+	return v
+}
+
+// Context returns the Context used for v's arithmetic.
+func (v Value) Context() *Context {
+	if v.ctx == nil {
+		return &ValueContext
+	}
+	return v.ctx
+}
+
+// Decimal returns a new Decimal holding the value of v.
+func (v Value) Decimal() *Decimal {
+	return new(Decimal).Set(&v.d)
+}
+
+// Err returns the first error encountered while computing v, if any.
+func (v Value) Err() error {
+	return v.err
+}
+
+// Flags returns the Conditions accumulated while computing v.
+func (v Value) Flags() Condition {
+	return v.flags
+}
+
+// apply1 returns the result of op on v. If v already has an error, v is
+// returned unchanged.
+func (v Value) apply1(op func(c *Context, d, x *Decimal) (Condition, error)) Value {
+	if v.err != nil {
+		return v
+	}
+	r := Value{ctx: v.ctx, flags: v.flags}
+	res, err := op(v.Context(), &r.d, &v.d)
+	r.flags |= res
+	r.err = err
+	return r
+}
+
+// apply2 returns the result of op on v and x, using v's Context. If either
+// operand already has an error, the error is carried to the result.
+func (v Value) apply2(x Value, op func(c *Context, d, x, y *Decimal) (Condition, error)) Value {
+	if v.err != nil {
+		return v
+	}
+	if x.err != nil {
+		v.flags |= x.flags
+		v.err = x.err
+		return v
+	}
+	r := Value{ctx: v.ctx, flags: v.flags | x.flags}
+	res, err := op(v.Context(), &r.d, &v.d, &x.d)
+	r.flags |= res
+	r.err = err
+	return r
+}
+
+// Add returns v+x.
+func (v Value) Add(x Value) Value {
+	return v.apply2(x, (*Context).Add)
+}
+
+// Sub returns v-x.
+func (v Value) Sub(x Value) Value {
+	return v.apply2(x, (*Context).Sub)
+}
+
+// Mul returns v*x.
+func (v Value) Mul(x Value) Value {
+	return v.apply2(x, (*Context).Mul)
+}
+
+// Quo returns v/x.
+func (v Value) Quo(x Value) Value {
+	return v.apply2(x, (*Context).Quo)
+}
+
+// QuoInteger returns the integer part of v/x.
+func (v Value) QuoInteger(x Value) Value {
+	return v.apply2(x, (*Context).QuoInteger)
+}
+
+// Rem returns the remainder part of v/x.
+func (v Value) Rem(x Value) Value {
+	return v.apply2(x, (*Context).Rem)
+}
+
+// Pow returns v**x.
+func (v Value) Pow(x Value) Value {
+	return v.apply2(x, (*Context).Pow)
+}
+
+// Abs returns |v|.
+func (v Value) Abs() Value {
+	return v.apply1((*Context).Abs)
+}
+
+// Neg returns -v.
+func (v Value) Neg() Value {
+	return v.apply1((*Context).Neg)
+}
+
+// Sqrt returns the square root of v.
+func (v Value) Sqrt() Value {
+	return v.apply1((*Context).Sqrt)
+}
+
+// Cbrt returns the cube root of v.
+func (v Value) Cbrt() Value {
+	return v.apply1((*Context).Cbrt)
+}
+
+// Exp returns e**v.
+func (v Value) Exp() Value {
+	return v.apply1((*Context).Exp)
+}
+
+// Ln returns the natural log of v.
+func (v Value) Ln() Value {
+	return v.apply1((*Context).Ln)
+}
+
+// Log10 returns the base 10 log of v.
+func (v Value) Log10() Value {
+	return v.apply1((*Context).Log10)
+}
+
+// Ceil returns the smallest integer >= v.
+func (v Value) Ceil() Value {
+	return v.apply1((*Context).Ceil)
+}
+
+// Floor returns the largest integer <= v.
+func (v Value) Floor() Value {
+	return v.apply1((*Context).Floor)
+}
+
+// Round returns v rounded according to v's Context.
+func (v Value) Round() Value {
+	return v.apply1((*Context).Round)
+}
+
+// Quantize returns v adjusted and rounded as necessary so it is represented
+// with exponent exp.
+func (v Value) Quantize(exp int32) Value {
+	return v.apply1(func(c *Context, d, x *Decimal) (Condition, error) {
+		return c.Quantize(d, x, exp)
+	})
+}
+
+// Cmp compares v and x and returns:
+//
+//	-1 if v <  x
+//	 0 if v == x
+//	+1 if v >  x
+//	undefined if v or x are NaN
+func (v Value) Cmp(x Value) int {
+	return v.d.Cmp(&x.d)
+}
+
+// Sign returns -1, 0 or +1 according to the sign of v, as defined by
+// (*Decimal).Sign.
+func (v Value) Sign() int {
+	return v.d.Sign()
+}
+
+// IsZero returns true if v == 0 or -0.
+func (v Value) IsZero() bool {
+	return v.d.IsZero()
+}
+
+// String formats v like (*Decimal).String.
+func (v Value) String() string {
+	return v.d.String()
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import "testing"
+
+func TestValue(t *testing.T) {
+	var zero Value
+	if s := zero.String(); s != "0" {
+		t.Fatalf("expected zero value to be 0, got %s", s)
+	}
+	if zero.Context() != &ValueContext {
+		t.Fatal("expected zero value to use ValueContext")
+	}
+
+	a := NewValueFromString("1.5")
+	b := NewValue(25, -1)
+	c := NewValueFromDecimal(New(3, 0))
+
+	tests := []struct {
+		v Value
+		r string
+	}{
+		{v: a.Add(b), r: "4.0"},
+		{v: a.Sub(b), r: "-1.0"},
+		{v: a.Mul(b).Mul(c), r: "11.25"},
+		{v: c.Quo(a), r: "2.000000000000000000000000000000000"},
+		{v: NewValue(1, 0).Quo(c), r: "0.3333333333333333333333333333333333"},
+		{v: b.QuoInteger(a), r: "1"},
+		{v: b.Rem(a), r: "1.0"},
+		{v: c.Pow(NewValue(2, 0)), r: "9"},
+		{v: b.Neg().Abs(), r: "2.5"},
+		{v: NewValue(16, 0).Sqrt(), r: "4.000000000000000000000000000000000"},
+		{v: NewValue(27, 0).Cbrt(), r: "3.000000000000000000000000000000000"},
+		{v: b.Floor(), r: "2"},
+		{v: b.Ceil(), r: "3"},
+		{v: b.Quantize(-3), r: "2.500"},
+		{v: NewValue(1, 0).WithContext(BaseContext.WithPrecision(5)).Quo(c), r: "0.33333"},
+		{v: NewValue(123456, 0).WithContext(BaseContext.WithPrecision(2)).Round(), r: "1.2E+5"},
+	}
+	for _, tc := range tests {
+		if err := tc.v.Err(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.r, err)
+		}
+		if s := tc.v.String(); s != tc.r {
+			t.Fatalf("expected %s, got %s", tc.r, s)
+		}
+	}
+
+	// Verify operands were not modified.
+	for _, tc := range []struct {
+		v Value
+		r string
+	}{
+		{v: a, r: "1.5"},
+		{v: b, r: "2.5"},
+		{v: c, r: "3"},
+	} {
+		if s := tc.v.String(); s != tc.r {
+			t.Fatalf("operand modified: expected %s, got %s", tc.r, s)
+		}
+	}
+
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || a.Cmp(NewValue(15, -1)) != 0 {
+		t.Fatal("unexpected Cmp result")
+	}
+	if b.Neg().Sign() != -1 || !NewValue(0, 3).IsZero() {
+		t.Fatal("unexpected Sign result")
+	}
+}
+
+func TestValueDecimal(t *testing.T) {
+	d := New(1234, -2)
+	v := NewValueFromDecimal(d)
+	d.SetInt64(7)
+	if s := v.String(); s != "12.34" {
+		t.Fatalf("expected 12.34, got %s", s)
+	}
+	out := v.Decimal()
+	out.SetInt64(8)
+	if s := v.String(); s != "12.34" {
+		t.Fatalf("expected 12.34, got %s", s)
+	}
+
+	// Large coefficients are stored outside of the inline array and must not
+	// be shared with the Decimal returned by the Decimal method.
+	big := NewValueFromString("123456789012345678901234567890123456789")
+	out = big.Decimal()
+	out.Coeff.Add(&out.Coeff, bigOne)
+	if s := big.String(); s != "123456789012345678901234567890123456789" {
+		t.Fatalf("unexpected value: %s", s)
+	}
+}
+
+func TestValueErr(t *testing.T) {
+	bad := NewValueFromString("abc")
+	if bad.Err() == nil {
+		t.Fatal("expected parse error")
+	}
+	if err := NewValue(1, 0).Add(bad).Err(); err == nil {
+		t.Fatal("expected error to be carried from operand")
+	}
+
+	quo := NewValue(1, 0).Quo(NewValue(0, 0))
+	if quo.Err() == nil {
+		t.Fatal("expected division by zero error")
+	}
+	if !quo.Flags().DivisionByZero() {
+		t.Fatalf("expected DivisionByZero flag, got %s", quo.Flags())
+	}
+	// Subsequent operations don't occur and don't change the error.
+	r := quo.Sub(NewValue(1, 0)).Mul(NewValue(2, 0))
+	if r.Err() != quo.Err() {
+		t.Fatalf("expected %v, got %v", quo.Err(), r.Err())
+	}
+	if s := r.String(); s != "Infinity" {
+		t.Fatalf("expected Infinity, got %s", s)
+	}
+
+	// Flags accumulate across operations.
+	c := BaseContext.WithPrecision(2)
+	r = NewValue(1, 0).WithContext(c).Quo(NewValue(3, 0)).Add(NewValue(1, 0))
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Flags() != Inexact|Rounded {
+		t.Fatalf("expected inexact, rounded, got %s", r.Flags())
+	}
+}
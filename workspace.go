@@ -0,0 +1,159 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+// Workspace holds temporary values that are reused across the calls of a
+// Context whose Workspace field points to it. Without a Workspace, Exp, Ln,
+// Log10, Pow, Sqrt and Cbrt allocate new temporary Decimals, Contexts and
+// loop states on every call, and the arithmetic operations they perform
+// allocate new coefficients and powers of ten of many digits. With one,
+// these are kept in the Workspace and reused by the next call. This is
+// useful when the same function is computed for many values.
+//
+// The zero value is an empty Workspace ready to use. A Workspace is not safe
+// for concurrent use: a Context with a Workspace, and Contexts sharing one,
+// must not be used concurrently.
+type Workspace struct {
+	// Temporaries of the arithmetic operations.
+	round struct{ y, m BigInt }
+	quo   struct{ dividend, divisor, rem BigInt }
+	add   BigInt
+
+	// pow10 caches the powers of ten above pow10LookupTable, 10^n in
+	// pow10[n-powerTenTableSize-1].
+	pow10 []BigInt
+
+	// Contexts and temporaries of the functions, each of which calls the
+	// others only in a fixed order: Pow calls integerPower, Ln and Exp,
+	// Log10 calls Ln, Ln calls Exp, and Exp calls integerPower.
+	contexts     [numWorkspaceContexts]Context
+	exp          expWorkspace
+	ln           lnWorkspace
+	log10        log10Workspace
+	pow          powWorkspace
+	integerPower integerPowerWorkspace
+	sqrt         sqrtWorkspace
+	cbrt         cbrtWorkspace
+}
+
+// maxWorkspacePow10 is the largest power of ten cached in a Workspace.
+const maxWorkspacePow10 = 4096
+
+type expWorkspace struct {
+	tmp1, tmp2, k, r, ra, sum Decimal
+	tmpE                      BigInt
+}
+
+type lnWorkspace struct {
+	tmp1, tmp2, tmp3, tmp4, z, resAdjust Decimal
+	eps                                  Decimal
+	loop                                 loop
+}
+
+type log10Workspace struct {
+	z Decimal
+}
+
+type powWorkspace struct {
+	integ, frac, tmp, z Decimal
+}
+
+type integerPowerWorkspace struct {
+	b BigInt
+	n Decimal
+}
+
+type sqrtWorkspace struct {
+	f, approx, tmp Decimal
+}
+
+type cbrtWorkspace struct {
+	ax, z, z0 Decimal
+	loop      loop
+}
+
+// Indexes of the functions that derive a Context with a different precision
+// from the Context they are called with, in Workspace.contexts.
+const (
+	workspaceExp = iota
+	workspaceLn
+	workspaceLog10
+	workspacePow
+	workspaceSqrt
+	workspaceCbrt
+	numWorkspaceContexts
+)
+
+// derivedContext returns a copy of base with precision p and the Workspace
+// of c. If c has a Workspace, the copy is the Context of function f in it
+// instead of a new one.
+func (c *Context) derivedContext(f int, base *Context, p uint32) *Context {
+	var r *Context
+	if w := c.Workspace; w != nil {
+		r = &w.contexts[f]
+	} else {
+		r = new(Context)
+	}
+	*r = *base
+	r.Precision = p
+	r.Workspace = c.Workspace
+	return r
+}
+
+// tableExp10 is like the tableExp10 function, but uses the powers of ten
+// cached in c.Workspace if c has one.
+func (c *Context) tableExp10(x int64, tmp *BigInt) *BigInt {
+	if w := c.Workspace; w != nil && x <= maxWorkspacePow10 {
+		return w.exp10(x)
+	}
+	return tableExp10(x, tmp)
+}
+
+// numDigits is like NumDigits, but uses the powers of ten cached in
+// c.Workspace if c has one.
+func (c *Context) numDigits(b *BigInt) int64 {
+	bl := b.BitLen()
+	if c.Workspace == nil || bl <= digitsTableSize {
+		return NumDigits(b)
+	}
+	n := int64(float64(bl) / digitsToBitsRatio)
+	if n > maxWorkspacePow10 {
+		return NumDigits(b)
+	}
+	if b.CmpAbs(c.Workspace.exp10(n)) >= 0 {
+		n++
+	}
+	return n
+}
+
+// exp10 returns 10^x for 0 <= x <= maxWorkspacePow10. The returned value
+// must not be mutated.
+func (w *Workspace) exp10(x int64) *BigInt {
+	if x <= powerTenTableSize {
+		return &pow10LookupTable[x]
+	}
+	i := int(x - powerTenTableSize - 1)
+	for len(w.pow10) <= i {
+		n := len(w.pow10)
+		w.pow10 = append(w.pow10, BigInt{})
+		p := &w.pow10[n]
+		if n == 0 {
+			p.Mul(&pow10LookupTable[powerTenTableSize], bigTen)
+		} else {
+			p.Mul(&w.pow10[n-1], bigTen)
+		}
+	}
+	return &w.pow10[i]
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package apd
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// TestWorkspace checks that the functions give the same results with a
+// Workspace, which is shared by all of them and reused across precisions,
+// as without one.
+func TestWorkspace(t *testing.T) {
+	rng := rand.New(rand.NewSource(20261018))
+	funcs := []struct {
+		name string
+		fn   func(c *Context, d, x, y *Decimal) (Condition, error)
+	}{
+		{"Exp", func(c *Context, d, x, _ *Decimal) (Condition, error) { return c.Exp(d, x) }},
+		{"Ln", func(c *Context, d, x, _ *Decimal) (Condition, error) { return c.Ln(d, x) }},
+		{"Log10", func(c *Context, d, x, _ *Decimal) (Condition, error) { return c.Log10(d, x) }},
+		{"Sqrt", func(c *Context, d, x, _ *Decimal) (Condition, error) { return c.Sqrt(d, x) }},
+		{"Cbrt", func(c *Context, d, x, _ *Decimal) (Condition, error) { return c.Cbrt(d, x) }},
+		{"Pow", (*Context).Pow},
+		{"Quo", (*Context).Quo},
+		{"Mul", (*Context).Mul},
+		{"Add", (*Context).Add},
+	}
+	randDecimal := func() *Decimal {
+		var d Decimal
+		d.Coeff.SetString(randDigits(rng, 1+rng.Intn(60)), 10)
+		d.Exponent = int32(rng.Intn(81) - 60)
+		d.Negative = rng.Intn(4) == 0
+		return &d
+	}
+	ws := new(Workspace)
+	for i := 0; i < 2000; i++ {
+		f := funcs[rng.Intn(len(funcs))]
+		c := BaseContext.WithPrecision(uint32(1 + rng.Intn(150)))
+		c.Traps = 0
+		x, y := randDecimal(), randDecimal()
+		if f.name == "Pow" {
+			// Keep the result within a reasonable range.
+			y.Exponent = -int32(y.NumDigits()) + int32(rng.Intn(3))
+			x.Negative = false
+		}
+		var want, got Decimal
+		wantRes, wantErr := f.fn(c, &want, x, y)
+		c.Workspace = ws
+		gotRes, gotErr := f.fn(c, &got, x, y)
+		if got.String() != want.String() || gotRes != wantRes || fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+			t.Fatalf("%s(%s, %s) at precision %d: got %s, %s, %v; expected %s, %s, %v",
+				f.name, x, y, c.Precision, &got, gotRes, gotErr, &want, wantRes, wantErr)
+		}
+		// The result may be an operand.
+		gotRes, gotErr = f.fn(c, x, x, y)
+		if x.String() != want.String() || gotRes != wantRes || fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+			t.Fatalf("%s in place at precision %d: got %s, %s, %v; expected %s, %s, %v",
+				f.name, c.Precision, x, gotRes, gotErr, &want, wantRes, wantErr)
+		}
+	}
+}